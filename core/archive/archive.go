@@ -0,0 +1,214 @@
+// Package archive reads and writes tenant snapshots holding the schema, relationships and attributes of a tenant
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Version is the archive layout version written by this cli
+const Version = 1
+
+// file names inside the archive
+const (
+	manifestFile         = "manifest.json"
+	schemaFile           = "schema.perm"
+	schemaDefinitionFile = "schema.json"
+	relationshipsFile    = "relationships.ndjson"
+	attributesFile       = "attributes.ndjson"
+)
+
+// Manifest describes the content of an archive
+type Manifest struct {
+	Version       int       `json:"version"`
+	Tenant        string    `json:"tenant"`
+	SchemaVersion string    `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Relationships int       `json:"relationships"`
+	Attributes    int       `json:"attributes"`
+}
+
+// Archive is a snapshot of a tenant
+type Archive struct {
+	Manifest Manifest
+	// Schema is the schema source in the permify dsl, empty when it is not known
+	Schema           string
+	SchemaDefinition *v1.SchemaDefinition
+	Tuples           []*v1.Tuple
+	Attributes       []*v1.Attribute
+}
+
+// Write stores the archive as a gzip compressed tar file at path
+func Write(path string, a *Archive) error {
+	a.Manifest.Version = Version
+	a.Manifest.Relationships = len(a.Tuples)
+	a.Manifest.Attributes = len(a.Attributes)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest, err := json.MarshalIndent(a.Manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	schemaDefinition, err := protojson.MarshalOptions{UseProtoNames: true, Multiline: true}.Marshal(a.SchemaDefinition)
+	if err != nil {
+		return err
+	}
+	relationships, err := marshalLines(a.Tuples)
+	if err != nil {
+		return err
+	}
+	attributes, err := marshalLines(a.Attributes)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{manifestFile, manifest},
+		{schemaFile, []byte(a.Schema)},
+		{schemaDefinitionFile, schemaDefinition},
+		{relationshipsFile, relationships},
+		{attributesFile, attributes},
+	}
+	for _, f := range files {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name:    f.name,
+			Mode:    0o600,
+			Size:    int64(len(f.content)),
+			ModTime: a.Manifest.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(f.content)
+		if err != nil {
+			return err
+		}
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Read loads the archive stored at path
+func Read(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a tenant archive: %w", path, err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	files := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = content
+	}
+
+	a := &Archive{SchemaDefinition: &v1.SchemaDefinition{}}
+	manifest, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a tenant archive: missing %s", path, manifestFile)
+	}
+	err = json.Unmarshal(manifest, &a.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	if a.Manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported archive version %d, this permctl supports version %d", a.Manifest.Version, Version)
+	}
+
+	a.Schema = string(files[schemaFile])
+	if len(files[schemaDefinitionFile]) > 0 {
+		err = protojson.Unmarshal(files[schemaDefinitionFile], a.SchemaDefinition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.Tuples, err = unmarshalLines(files[relationshipsFile], func() *v1.Tuple { return &v1.Tuple{} })
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", relationshipsFile, err)
+	}
+	a.Attributes, err = unmarshalLines(files[attributesFile], func() *v1.Attribute { return &v1.Attribute{} })
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", attributesFile, err)
+	}
+	return a, nil
+}
+
+// marshalLines encodes every message as protojson on its own line
+func marshalLines[T proto.Message](messages []T) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	for _, message := range messages {
+		line, err := protojson.Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(line)
+		buffer.WriteByte('\n')
+	}
+	return buffer.Bytes(), nil
+}
+
+// unmarshalLines decodes one protojson message per line
+func unmarshalLines[T proto.Message](content []byte, newMessage func() T) ([]T, error) {
+	messages := []T{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		message := newMessage()
+		err := protojson.Unmarshal(scanner.Bytes(), message)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, scanner.Err()
+}
@@ -0,0 +1,176 @@
+// Package assertion reads permission check assertions and runs them against permify
+package assertion
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gopkg.in/yaml.v3"
+)
+
+// Check asserts the result of permissions of an entity for a subject, e.g.
+//
+//	entity: repository:1
+//	subject: user:1
+//	context:
+//	  tuples:
+//	    - repository:1#owner@user:1
+//	assertions:
+//	  view: true
+//	  delete: false
+type Check struct {
+	Entity     string          `yaml:"entity"`
+	Subject    string          `yaml:"subject"`
+	Context    *Context        `yaml:"context"`
+	Assertions map[string]bool `yaml:"assertions"`
+}
+
+// Context holds the contextual tuples, attributes and data sent with the requests of a check or
+// filter. Tuples and attributes use the text notation of data files.
+type Context struct {
+	Tuples     []string               `yaml:"tuples"`
+	Attributes []string               `yaml:"attributes"`
+	Data       map[string]interface{} `yaml:"data"`
+}
+
+// Request returns the context of a permission request, nil when c is nil
+func (c *Context) Request() (*v1.Context, error) {
+	if c == nil {
+		return nil, nil
+	}
+	lines := append(slices.Clone(c.Tuples), c.Attributes...)
+	data, err := datafile.Parse([]byte(strings.Join(lines, "\n")), datafile.Text)
+	if err != nil {
+		return nil, fmt.Errorf("context: %w", err)
+	}
+	requestContext := &v1.Context{
+		Tuples:     data.Tuples,
+		Attributes: data.Attributes,
+	}
+	if len(c.Data) > 0 {
+		requestContext.Data, err = structpb.NewStruct(c.Data)
+		if err != nil {
+			return nil, fmt.Errorf("context data: %w", err)
+		}
+	}
+	return requestContext, nil
+}
+
+// File is an assertions file holding a list of checks
+type File struct {
+	Checks []*Check `yaml:"checks"`
+}
+
+// Read loads an assertions file, yaml or json
+func Read(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &File{}
+	if err := yaml.Unmarshal(content, file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(file.Checks) == 0 {
+		return nil, fmt.Errorf("%s: no checks found", path)
+	}
+	return file, nil
+}
+
+// Result is the outcome of a single assertion, Err is set when the request failed
+type Result struct {
+	// Scenario is the name of the test scenario of the assertion, empty for assertions files
+	Scenario string
+	Name     string
+	Expected string
+	Actual   string
+	Duration time.Duration
+	Err      error
+}
+
+// Passed reports whether the request succeeded with the expected result
+func (r Result) Passed() bool {
+	return r.Err == nil && r.Actual == r.Expected
+}
+
+func (r Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: %s", r.Name, r.Err)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s", r.Name, r.Expected, r.Actual)
+}
+
+func result(allowed bool) string {
+	if allowed {
+		return "allowed"
+	}
+	return "denied"
+}
+
+// RunChecks runs every assertion of the checks against the schema version of the tenant, the head when
+// schemaVersion is empty. Results follow the order of the checks, assertions of a check are sorted by permission.
+func RunChecks(ctx context.Context, client v1.PermissionClient, tenant, schemaVersion string, checks []*Check) []Result {
+	results := []Result{}
+	for _, check := range checks {
+		entity, entityErr := utils.ParseEntity(check.Entity)
+		subject, subjectErr := utils.ParseSubject(check.Subject)
+		requestContext, contextErr := check.Context.Request()
+		for _, permission := range sortedKeys(check.Assertions) {
+			r := Result{
+				Name:     fmt.Sprintf("%s#%s@%s", check.Entity, permission, check.Subject),
+				Expected: result(check.Assertions[permission]),
+			}
+			switch {
+			case entityErr != nil:
+				r.Err = entityErr
+			case subjectErr != nil:
+				r.Err = subjectErr
+			case contextErr != nil:
+				r.Err = contextErr
+			default:
+				start := time.Now()
+				allowed, err := Allowed(ctx, client, tenant, schemaVersion, entity, permission, subject, requestContext)
+				r.Actual, r.Err, r.Duration = result(allowed), err, time.Since(start)
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// Allowed reports whether the subject has the permission on the entity, requestContext may be nil
+func Allowed(ctx context.Context, client v1.PermissionClient, tenant, schemaVersion string, entity *v1.Entity, permission string, subject *v1.Subject, requestContext *v1.Context) (bool, error) {
+	checkResponse, err := client.Check(ctx, &v1.PermissionCheckRequest{
+		TenantId: tenant,
+		Metadata: &v1.PermissionCheckRequestMetadata{
+			SchemaVersion: schemaVersion,
+			Depth:         50,
+		},
+		Entity:     entity,
+		Permission: permission,
+		Subject:    subject,
+		Context:    requestContext,
+	})
+	if err != nil {
+		return false, err
+	}
+	return checkResponse.Can == v1.CheckResult_CHECK_RESULT_ALLOWED, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
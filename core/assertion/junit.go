@@ -0,0 +1,91 @@
+package assertion
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results as a junit xml report with a test suite per scenario
+func WriteJUnit(w io.Writer, name string, results []Result) error {
+	report := &junitTestSuites{Name: name}
+	suites := map[string]*junitTestSuite{}
+	var total time.Duration
+	for _, r := range results {
+		suite, ok := suites[r.Scenario]
+		if !ok {
+			suite = &junitTestSuite{Name: r.Scenario}
+			suites[r.Scenario] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+		testCase := &junitTestCase{Name: r.Name, Classname: name + "." + r.Scenario, Time: seconds(r.Duration)}
+		switch {
+		case r.Err != nil:
+			testCase.Error = &junitProblem{Message: r.Err.Error(), Text: r.String()}
+			suite.Errors++
+			report.Errors++
+		case !r.Passed():
+			testCase.Failure = &junitProblem{Message: fmt.Sprintf("expected %s, got %s", r.Expected, r.Actual), Text: r.String()}
+			suite.Failures++
+			report.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suite.duration += r.Duration
+		report.Tests++
+		total += r.Duration
+	}
+	for _, suite := range report.Suites {
+		suite.Time = seconds(suite.duration)
+	}
+	report.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
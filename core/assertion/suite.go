@@ -0,0 +1,219 @@
+package assertion
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a test file in the layout of the permify validation yaml. Schema holds the perm source,
+// or the path of a perm file relative to the test file. Relationships and attributes use the text
+// notation of data files, e.g. organization:1#admin@user:1 and repository:1$public|boolean:true.
+type Suite struct {
+	Schema        string      `yaml:"schema"`
+	Relationships []string    `yaml:"relationships"`
+	Attributes    []string    `yaml:"attributes"`
+	Scenarios     []*Scenario `yaml:"scenarios"`
+}
+
+// Scenario groups the assertions of a test file
+type Scenario struct {
+	Name           string           `yaml:"name"`
+	Description    string           `yaml:"description"`
+	Checks         []*Check         `yaml:"checks"`
+	EntityFilters  []*EntityFilter  `yaml:"entity_filters"`
+	SubjectFilters []*SubjectFilter `yaml:"subject_filters"`
+}
+
+// EntityFilter asserts the ids of the entities of a type the subject has each permission on
+type EntityFilter struct {
+	EntityType string              `yaml:"entity_type"`
+	Subject    string              `yaml:"subject"`
+	Context    *Context            `yaml:"context"`
+	Assertions map[string][]string `yaml:"assertions"`
+}
+
+// SubjectFilter asserts the ids of the subjects of a type, e.g. user or team#member, that have each permission on the entity
+type SubjectFilter struct {
+	SubjectReference string              `yaml:"subject_reference"`
+	Entity           string              `yaml:"entity"`
+	Context          *Context            `yaml:"context"`
+	Assertions       map[string][]string `yaml:"assertions"`
+}
+
+// ReadSuite loads a test file and the schema file it references. Unknown fields are errors, so that
+// a misspelled key does not drop assertions, and so are scenarios without assertions.
+func ReadSuite(path string) (*Suite, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	suite := &Suite{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(suite); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if strings.TrimSpace(suite.Schema) == "" {
+		return nil, fmt.Errorf("%s: schema is empty", path)
+	}
+	if len(suite.Scenarios) == 0 {
+		return nil, fmt.Errorf("%s: no scenarios found", path)
+	}
+	for i, scenario := range suite.Scenarios {
+		if scenario.assertions() == 0 {
+			name := scenario.Name
+			if name == "" {
+				name = fmt.Sprintf("scenario %d", i+1)
+			}
+			return nil, fmt.Errorf("%s: %s has no assertions", path, name)
+		}
+	}
+	if schemaFile := strings.TrimSpace(suite.Schema); strings.HasSuffix(schemaFile, ".perm") && !strings.Contains(schemaFile, "\n") {
+		if !filepath.IsAbs(schemaFile) {
+			schemaFile = filepath.Join(filepath.Dir(path), schemaFile)
+		}
+		source, err := utils.ReadFileToString(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		suite.Schema = source
+	}
+	return suite, nil
+}
+
+// assertions returns the number of assertions of the scenario
+func (s *Scenario) assertions() int {
+	count := 0
+	for _, check := range s.Checks {
+		count += len(check.Assertions)
+	}
+	for _, filter := range s.EntityFilters {
+		count += len(filter.Assertions)
+	}
+	for _, filter := range s.SubjectFilters {
+		count += len(filter.Assertions)
+	}
+	return count
+}
+
+// Data returns the relationships and attributes of the suite
+func (s *Suite) Data() (*datafile.Data, error) {
+	lines := append(slices.Clone(s.Relationships), s.Attributes...)
+	return datafile.Parse([]byte(strings.Join(lines, "\n")), datafile.Text)
+}
+
+// Run runs the assertions of every scenario against the schema version of the tenant
+func (s *Suite) Run(ctx context.Context, client v1.PermissionClient, tenant, schemaVersion string) []Result {
+	results := []Result{}
+	for i, scenario := range s.Scenarios {
+		name := scenario.Name
+		if name == "" {
+			name = fmt.Sprintf("scenario %d", i+1)
+		}
+		scenarioResults := RunChecks(ctx, client, tenant, schemaVersion, scenario.Checks)
+		for _, filter := range scenario.EntityFilters {
+			scenarioResults = append(scenarioResults, runEntityFilter(ctx, client, tenant, schemaVersion, filter)...)
+		}
+		for _, filter := range scenario.SubjectFilters {
+			scenarioResults = append(scenarioResults, runSubjectFilter(ctx, client, tenant, schemaVersion, filter)...)
+		}
+		for _, r := range scenarioResults {
+			r.Scenario = name
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func runEntityFilter(ctx context.Context, client v1.PermissionClient, tenant, schemaVersion string, filter *EntityFilter) []Result {
+	results := []Result{}
+	subject, err := utils.ParseSubject(filter.Subject)
+	var requestContext *v1.Context
+	if err == nil {
+		requestContext, err = filter.Context.Request()
+	}
+	for _, permission := range sortedKeys(filter.Assertions) {
+		r := Result{
+			Name:     fmt.Sprintf("%s#%s@%s", filter.EntityType, permission, filter.Subject),
+			Expected: ids(filter.Assertions[permission]),
+			Err:      err,
+		}
+		if err == nil {
+			start := time.Now()
+			lookupResponse, err := client.LookupEntity(ctx, &v1.PermissionLookupEntityRequest{
+				TenantId: tenant,
+				Metadata: &v1.PermissionLookupEntityRequestMetadata{
+					SchemaVersion: schemaVersion,
+					Depth:         50,
+				},
+				EntityType: filter.EntityType,
+				Permission: permission,
+				Subject:    subject,
+				Context:    requestContext,
+			})
+			r.Duration, r.Err = time.Since(start), err
+			if err == nil {
+				r.Actual = ids(lookupResponse.GetEntityIds())
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func runSubjectFilter(ctx context.Context, client v1.PermissionClient, tenant, schemaVersion string, filter *SubjectFilter) []Result {
+	results := []Result{}
+	entity, err := utils.ParseEntity(filter.Entity)
+	var requestContext *v1.Context
+	if err == nil {
+		requestContext, err = filter.Context.Request()
+	}
+	subjectType, subjectRelation, _ := strings.Cut(filter.SubjectReference, "#")
+	for _, permission := range sortedKeys(filter.Assertions) {
+		r := Result{
+			Name:     fmt.Sprintf("%s#%s@%s", filter.Entity, permission, filter.SubjectReference),
+			Expected: ids(filter.Assertions[permission]),
+			Err:      err,
+		}
+		if err == nil {
+			start := time.Now()
+			lookupResponse, err := client.LookupSubject(ctx, &v1.PermissionLookupSubjectRequest{
+				TenantId: tenant,
+				Metadata: &v1.PermissionLookupSubjectRequestMetadata{
+					SchemaVersion: schemaVersion,
+					Depth:         50,
+				},
+				Entity:           entity,
+				Permission:       permission,
+				SubjectReference: &v1.RelationReference{Type: subjectType, Relation: subjectRelation},
+				Context:          requestContext,
+			})
+			r.Duration, r.Err = time.Since(start), err
+			if err == nil {
+				r.Actual = ids(lookupResponse.GetSubjectIds())
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// ids formats ids in sorted order so that results do not depend on the order of the response
+func ids(values []string) string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return "[" + strings.Join(sorted, ", ") + "]"
+}
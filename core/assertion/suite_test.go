@@ -0,0 +1,163 @@
+package assertion
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/grpc"
+)
+
+const contextSuite = `schema: >-
+  entity user {}
+
+  entity repository {
+      relation owner @user
+      attribute public boolean
+      permission view = owner or public
+  }
+relationships:
+  - repository:1#owner@user:1
+scenarios:
+  - name: context
+    checks:
+      - entity: repository:2
+        subject: user:1
+        context:
+          tuples:
+            - repository:2#owner@user:1
+          attributes:
+            - repository:2$public|boolean:true
+          data:
+            ip_address: 10.0.0.1
+        assertions:
+          view: true
+    entity_filters:
+      - entity_type: repository
+        subject: user:1
+        context:
+          tuples:
+            - repository:3#owner@user:1
+        assertions:
+          view: ["1", "3"]
+    subject_filters:
+      - subject_reference: user
+        entity: repository:3
+        context:
+          tuples:
+            - repository:3#owner@user:2
+        assertions:
+          view: ["2"]
+`
+
+// recordingClient answers every request as expected by contextSuite and records the request contexts
+type recordingClient struct {
+	v1.PermissionClient
+	contexts []*v1.Context
+}
+
+func (c *recordingClient) Check(ctx context.Context, in *v1.PermissionCheckRequest, opts ...grpc.CallOption) (*v1.PermissionCheckResponse, error) {
+	c.contexts = append(c.contexts, in.Context)
+	return &v1.PermissionCheckResponse{Can: v1.CheckResult_CHECK_RESULT_ALLOWED}, nil
+}
+
+func (c *recordingClient) LookupEntity(ctx context.Context, in *v1.PermissionLookupEntityRequest, opts ...grpc.CallOption) (*v1.PermissionLookupEntityResponse, error) {
+	c.contexts = append(c.contexts, in.Context)
+	return &v1.PermissionLookupEntityResponse{EntityIds: []string{"3", "1"}}, nil
+}
+
+func (c *recordingClient) LookupSubject(ctx context.Context, in *v1.PermissionLookupSubjectRequest, opts ...grpc.CallOption) (*v1.PermissionLookupSubjectResponse, error) {
+	c.contexts = append(c.contexts, in.Context)
+	return &v1.PermissionLookupSubjectResponse{SubjectIds: []string{"2"}}, nil
+}
+
+func TestReadSuiteContext(t *testing.T) {
+	suite, err := ReadSuite(writeSuite(t, contextSuite))
+	if err != nil {
+		t.Fatalf("ReadSuite() error = %v", err)
+	}
+	client := &recordingClient{}
+	results := suite.Run(context.Background(), client, "t1", "")
+	for _, r := range results {
+		if !r.Passed() {
+			t.Errorf("assertion failed: %s", r)
+		}
+	}
+	if len(client.contexts) != 3 {
+		t.Fatalf("got %d requests, want 3", len(client.contexts))
+	}
+
+	check := client.contexts[0]
+	if len(check.GetTuples()) != 1 || len(check.GetAttributes()) != 1 {
+		t.Errorf("check context = %v, want a tuple and an attribute", check)
+	}
+	if ip := check.GetData().GetFields()["ip_address"].GetStringValue(); ip != "10.0.0.1" {
+		t.Errorf("check context data ip_address = %q", ip)
+	}
+	for i, want := range []string{"repository:3", "repository:3"} {
+		tuples := client.contexts[i+1].GetTuples()
+		if len(tuples) != 1 || tuples[0].GetEntity().GetType()+":"+tuples[0].GetEntity().GetId() != want {
+			t.Errorf("filter %d context tuples = %v", i+1, tuples)
+		}
+	}
+}
+
+func TestReadSuiteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "unknown key",
+			content: strings.Replace(contextSuite, "entity_filters", "entity_filter", 1),
+			want:    "field entity_filter not found",
+		},
+		{
+			name:    "unknown context key",
+			content: strings.Replace(contextSuite, "          data:", "          values:", 1),
+			want:    "field values not found",
+		},
+		{
+			name:    "no scenarios",
+			content: "schema: entity user {}\n",
+			want:    "no scenarios found",
+		},
+		{
+			name:    "scenario without assertions",
+			content: "schema: entity user {}\nscenarios:\n  - name: empty\n    checks:\n      - entity: user:1\n        subject: user:1\n",
+			want:    "empty has no assertions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSuite(writeSuite(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadSuite() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextErrors(t *testing.T) {
+	suite, err := ReadSuite(writeSuite(t, strings.Replace(contextSuite, "repository:2#owner@user:1", "repository:2#owner", 1)))
+	if err != nil {
+		t.Fatalf("ReadSuite() error = %v", err)
+	}
+	results := suite.Run(context.Background(), &recordingClient{}, "t1", "")
+	if results[0].Err == nil || !strings.Contains(results[0].Err.Error(), "context") {
+		t.Errorf("check with an invalid context tuple: %s", results[0])
+	}
+}
+
+func writeSuite(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "suite.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -4,9 +4,12 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/Permify/permify-cli/core/config"
 	"github.com/Permify/permify-cli/core/logger"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/templates"
 	"github.com/Permify/permify-cli/version"
 	"github.com/spf13/cobra"
@@ -46,6 +49,7 @@ func New(name, shortDescription, defaultConfigPath string) *Cli {
 	c.Cmd.PersistentFlags().String("config", defaultConfigPath, fmt.Sprintf("%s config file", c.Name))
 	c.Cmd.PersistentFlags().String("profile", "default", "profile name for config")
 	c.Cmd.PersistentFlags().String("schema", "", "schema version to use")
+	c.Cmd.PersistentFlags().StringP("output", "o", string(printer.JSON), fmt.Sprintf("output format (%s)", strings.Join(printer.Formats(), "|")))
 
 	return c
 }
@@ -55,9 +59,16 @@ func PersistentPreRun(cmd *cobra.Command, args []string) {
 	debugEnabled, _ := cmd.Flags().GetBool("debug")
 	os.Setenv(PermifyDebugEnv, fmt.Sprintf("%t", debugEnabled))
 	logger.Update(debugEnabled)
-	err := initializeConfig(cmd, args)
+	output, _ := cmd.Flags().GetString("output")
+	err := printer.SetFormat(output)
 	if err != nil {
-		logger.Log.Fatal(err)
+		logger.Log.Error(err)
+		os.Exit(ErrorExitCode(cmd, os.Args[1:]))
+	}
+	err = initializeConfig(cmd, args)
+	if err != nil {
+		logger.Log.Error(err)
+		os.Exit(ErrorExitCode(cmd, os.Args[1:]))
 	}
 }
 
@@ -72,16 +83,15 @@ func initializeConfig(cmd *cobra.Command, _ []string) error {
 	}
 
 	err = config.IsConfigured(configFile, profile)
+	if err != nil && cmd.Annotations[config.OfflineAnnotation] == "true" {
+		return nil
+	}
 	if err != nil {
 		logger.Log.Error(err)
 		logger.Log.Print("permctl is not configured. Please run `permctl configure`")
-		os.Exit(1)
+		os.Exit(ErrorExitCode(cmd, os.Args[1:]))
 	}
-	err = config.Load(configFile, profile)
-	if err != nil {
-		logger.Log.Fatal(err)
-	}
-	return nil
+	return config.Load(configFile, profile)
 }
 
 // Run - function to run on root cli command
@@ -91,10 +101,60 @@ func (c Cli) Run(cmd *cobra.Command, _ []string) {
 
 // Execute - run the root command
 func (c Cli) Execute() {
-	err := c.Cmd.Execute()
+	cmd, err := c.Cmd.ExecuteC()
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(ErrorExitCode(cmd, os.Args[1:]))
+	}
+}
+
+// ErrorExitCode returns the exit code of a failure of the flags or the setup of a command, 1 unless
+// the command sets config.ExitCodeAnnotation. The exit code flags are looked up in the arguments
+// too, as parsing stops at the first invalid flag and leaves the flags after it unset.
+func ErrorExitCode(cmd *cobra.Command, args []string) int {
+	code, err := strconv.Atoi(cmd.Annotations[config.ExitCodeAnnotation])
+	if err != nil {
+		return 1
+	}
+	flags := cmd.Annotations[config.ExitCodeFlagsAnnotation]
+	if flags == "" {
+		return code
+	}
+	for _, name := range strings.Split(flags, ",") {
+		if boolFlagSet(cmd, name, args) {
+			return code
+		}
+	}
+	return 1
+}
+
+// boolFlagSet reports whether the boolean flag is set to true on the command or in the arguments
+func boolFlagSet(cmd *cobra.Command, name string, args []string) bool {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return false
+	}
+	if flag.Changed {
+		return flag.Value.String() == "true"
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		value := ""
+		switch {
+		case arg == "--"+name || (flag.Shorthand != "" && arg == "-"+flag.Shorthand):
+			return true
+		case strings.HasPrefix(arg, "--"+name+"="):
+			value = strings.TrimPrefix(arg, "--"+name+"=")
+		case flag.Shorthand != "" && strings.HasPrefix(arg, "-"+flag.Shorthand+"="):
+			value = strings.TrimPrefix(arg, "-"+flag.Shorthand+"=")
+		default:
+			continue
+		}
+		set, err := strconv.ParseBool(value)
+		return err == nil && set
 	}
+	return false
 }
 
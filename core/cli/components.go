@@ -5,6 +5,7 @@ import (
 	"github.com/Permify/permify-cli/core/cmd/permission"
 	"github.com/Permify/permify-cli/core/cmd/schema"
 	"github.com/Permify/permify-cli/core/cmd/tenancy"
+	"github.com/Permify/permify-cli/core/cmd/test"
 	"github.com/spf13/cobra"
 )
 
@@ -14,9 +15,11 @@ func AddComponents(rootCmd *cobra.Command) {
 	tenancyCmd := tenancy.New()
 	dataCmd := data.New()
 	schemaCmd := schema.New()
+	testCmd := test.New()
 
 	rootCmd.AddCommand(permissionCmd)
 	rootCmd.AddCommand(tenancyCmd)
 	rootCmd.AddCommand(dataCmd)
 	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(testCmd)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Permify/permify-cli/core/client"
 	"github.com/Permify/permify-cli/core/config"
@@ -15,12 +16,23 @@ import (
 
 // ConfigureCmd provides the configure command on permctl
 func ConfigureCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:              "configure",
 		Short:            "configure permctl",
 		PersistentPreRun: persistentPreRun,
 		RunE:             runE,
 	}
+	cmd.Flags().String("ca-cert", "", "path to the ca certificate bundle used to verify the server")
+	cmd.Flags().String("client-cert", "", "path to the client certificate for mutual tls")
+	cmd.Flags().String("client-key", "", "path to the client private key for mutual tls")
+	cmd.Flags().String("server-name", "", "override the server name used to verify the server certificate")
+	cmd.Flags().Bool("insecure-skip-verify", false, "skip verification of the server certificate")
+	cmd.Flags().String("token", "", "bearer token or pre-shared key sent with every request")
+	cmd.Flags().String("token-file", "", "path to a file holding the bearer token")
+	cmd.Flags().String("token-command", "", "command printing the bearer token, run once per permctl invocation")
+	cmd.MarkFlagsRequiredTogether("client-cert", "client-key")
+	cmd.MarkFlagsMutuallyExclusive("token", "token-file", "token-command")
+	return cmd
 }
 
 func persistentPreRun(cmd *cobra.Command, args []string) {
@@ -102,27 +114,38 @@ func runE(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	resp, err := client.New(url)
+	config.CliConfig.PermifyURL = url
+	updateTLSConfig(cmd)
+	updateTokenConfig(cmd)
+	config.CliConfig.SslEnabled = strings.HasPrefix(url, "https") || config.CliConfig.TLS.Enabled()
 
-	// Todo: Implement pagination
-	tenants, err := resp.Tenancy.List(context.Background(), &v1.TenantListRequest{})
+	resp, err := client.New(config.CliConfig)
 	if err != nil {
 		logger.Log.Fatal(err)
 	}
 
-	tenantNames := []string{}
 	tenantIds := map[string]string{}
-	for _, tenant := range tenants.Tenants {
-		nameID := fmt.Sprintf("%s {%s}", tenant.Name, tenant.Id)
-		tenantNames = append(tenantNames, nameID)
-		tenantIds[nameID] = tenant.Id
+	loadTenants := func(continuousToken string) ([]string, string, error) {
+		tenants, err := resp.Tenancy.List(context.Background(), &v1.TenantListRequest{
+			PageSize:        tenantPageSize,
+			ContinuousToken: continuousToken,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		tenantNames := []string{}
+		for _, tenant := range tenants.Tenants {
+			nameID := fmt.Sprintf("%s {%s}", tenant.Name, tenant.Id)
+			tenantNames = append(tenantNames, nameID)
+			tenantIds[nameID] = tenant.Id
+		}
+		return tenantNames, tenants.ContinuousToken, nil
 	}
-	
-	tenant, err := tui.Choice("Select a tenant: ", tenantNames)
+
+	tenant, err := tui.PagedChoice("Select a tenant", loadTenants)
 	if err != nil {
-		logger.Log.Error(err)
+		logger.Log.Fatal(err)
 	}
-	config.CliConfig.PermifyURL = url
 	config.CliConfig.Tenant = tenantIds[tenant]
 	err = config.Write()
 	if err != nil {
@@ -131,3 +154,43 @@ func runE(cmd *cobra.Command, _ []string) error {
 	logger.Log.Info("successfully configured ", "config file", configFile)
 	return nil
 }
+
+// updateTLSConfig overrides the tls settings of the profile with the flags passed to configure
+func updateTLSConfig(cmd *cobra.Command) {
+	if cmd.Flags().Changed("ca-cert") {
+		config.CliConfig.TLS.CACert, _ = cmd.Flags().GetString("ca-cert")
+	}
+	if cmd.Flags().Changed("client-cert") {
+		config.CliConfig.TLS.ClientCert, _ = cmd.Flags().GetString("client-cert")
+	}
+	if cmd.Flags().Changed("client-key") {
+		config.CliConfig.TLS.ClientKey, _ = cmd.Flags().GetString("client-key")
+	}
+	if cmd.Flags().Changed("server-name") {
+		config.CliConfig.TLS.ServerName, _ = cmd.Flags().GetString("server-name")
+	}
+	if cmd.Flags().Changed("insecure-skip-verify") {
+		config.CliConfig.TLS.InsecureSkipVerify, _ = cmd.Flags().GetBool("insecure-skip-verify")
+	}
+}
+
+// updateTokenConfig replaces the token source of the profile when one of the token flags is passed
+func updateTokenConfig(cmd *cobra.Command) {
+	for _, flag := range []string{"token", "token-file", "token-command"} {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		value, _ := cmd.Flags().GetString(flag)
+		config.CliConfig.Token = ""
+		config.CliConfig.TokenFile = ""
+		config.CliConfig.TokenCommand = ""
+		switch flag {
+		case "token":
+			config.CliConfig.Token = value
+		case "token-file":
+			config.CliConfig.TokenFile = value
+		case "token-command":
+			config.CliConfig.TokenCommand = value
+		}
+	}
+}
@@ -2,3 +2,6 @@ package cli
 
 // PermifyDebugEnv is the environment variable to set a debug flag for the cli
 const PermifyDebugEnv = "PERMIFY_CLI_DEBUG"
+
+// tenantPageSize is the number of tenants loaded at once by the configure tenant picker
+const tenantPageSize = 20
@@ -2,19 +2,95 @@
 package client
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Permify/permify-cli/core/config"
 	permify "github.com/Permify/permify-go/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// New initializes a new permify client
-func New(endpoint string) (*permify.Client, error) {
+// New initializes a new permify client for the given profile configuration
+func New(cfg config.CoreConfig) (*permify.Client, error) {
+	transportCredentials, err := TransportCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
+	}
+
+	token, err := ResolveToken(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		secure := transportCredentials.Info().SecurityProtocol == "tls"
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(token, secure)))
+	}
+
 	client, err := permify.NewClient(
 		permify.Config{
-			Endpoint: endpoint,
+			Endpoint: Endpoint(cfg.PermifyURL),
 		},
-		// Todo: Implement secure call with tls certificate
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		opts...,
 	)
 	return client, err
 }
+
+// Endpoint strips the url scheme from the permify url so that it can be dialed by grpc
+func Endpoint(permifyURL string) string {
+	endpoint := strings.TrimPrefix(permifyURL, "https://")
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	return strings.TrimSuffix(endpoint, "/")
+}
+
+// TransportCredentials builds the grpc transport credentials for the profile.
+// Insecure credentials are used unless the url is https or tls settings are present.
+func TransportCredentials(cfg config.CoreConfig) (credentials.TransportCredentials, error) {
+	if !cfg.SslEnabled && !cfg.TLS.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func newTLSConfig(t config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // explicitly requested by the profile
+	}
+
+	if t.CACert != "" {
+		caCert, err := os.ReadFile(t.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca certificate: %w", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificates found in %s", t.CACert)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if t.ClientCert != "" || t.ClientKey != "" {
+		if t.ClientCert == "" || t.ClientKey == "" {
+			return nil, fmt.Errorf("both client certificate and client key are required for mutual tls")
+		}
+		certificate, err := tls.LoadX509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/Permify/permify-cli/core/config"
+	"google.golang.org/grpc/credentials"
+)
+
+// ResolveToken returns the bearer token of the profile. The token is taken from
+// the token setting, the token file or the output of the token command, in that order.
+func ResolveToken(cfg config.CoreConfig) (string, error) {
+	if cfg.Token != "" {
+		return cfg.Token, nil
+	}
+	if cfg.TokenFile != "" {
+		data, err := os.ReadFile(cfg.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	if cfg.TokenCommand != "" {
+		shell, flag := "sh", "-c"
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+		out, err := exec.Command(shell, flag, cfg.TokenCommand).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to run token command: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return "", nil
+}
+
+// tokenCredentials wraps the token as per rpc credentials. Secure credentials are
+// used on tls connections so that grpc refuses to send the token in plain text.
+func tokenCredentials(token string, secure bool) credentials.PerRPCCredentials {
+	metadata := map[string]string{
+		"authorization": "Bearer " + token,
+	}
+	if secure {
+		return secureTokenCredentials(metadata)
+	}
+	return nonSecureTokenCredentials(metadata)
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/tui"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// DefaultBatchSize is the largest number of tuples or attributes permify accepts in a single write request
+const DefaultBatchSize = 100
+
+// BulkWriteOptions configures how tuples and attributes are split into write requests
+type BulkWriteOptions struct {
+	TenantID      string
+	SchemaVersion string
+	BatchSize     int
+	Concurrency   int
+	// Progress reports the written tuples and attributes, a new progress bar is shown when it is nil
+	Progress *tui.Progress
+}
+
+// BulkWriteSummary is printed after a bulk write
+type BulkWriteSummary struct {
+	Tuples     int `json:"tuples"`
+	Attributes int `json:"attributes"`
+	Batches    int `json:"batches"`
+	// SnapToken is the snap token of the last batch, it is written after the others and covers every batch
+	SnapToken string `json:"snap_token,omitempty"`
+}
+
+// BulkWrite writes the tuples and attributes in batches of BatchSize using up to Concurrency
+// parallel requests. The first failing batch cancels the remaining ones.
+// The last batch is written alone once the others succeeded, so that its snap token covers all of them.
+func BulkWrite(ctx context.Context, dataClient v1.DataClient, data *datafile.Data, opts BulkWriteOptions) (*BulkWriteSummary, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+
+	requests := []*v1.DataWriteRequest{}
+	for start := 0; start < max(len(data.Tuples), len(data.Attributes)); start += opts.BatchSize {
+		requests = append(requests, &v1.DataWriteRequest{
+			TenantId: opts.TenantID,
+			Metadata: &v1.DataWriteRequestMetadata{
+				SchemaVersion: opts.SchemaVersion,
+			},
+			Tuples:     batch(data.Tuples, start, opts.BatchSize),
+			Attributes: batch(data.Attributes, start, opts.BatchSize),
+		})
+	}
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = tui.NewProgress("writing data", len(data.Tuples)+len(data.Attributes))
+		defer progress.Finish()
+	}
+
+	snapToken, err := runBatches(ctx, len(requests), opts.Concurrency, func(ctx context.Context, i int) (string, error) {
+		response, err := dataClient.Write(ctx, requests[i])
+		if err != nil {
+			return "", fmt.Errorf("batch %d failed: %w", i+1, err)
+		}
+		progress.Add(len(requests[i].Tuples) + len(requests[i].Attributes))
+		return response.SnapToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BulkWriteSummary{
+		Tuples:     len(data.Tuples),
+		Attributes: len(data.Attributes),
+		Batches:    len(requests),
+		SnapToken:  snapToken,
+	}, nil
+}
+
+// runBatches runs the batches 0 to n-1 using up to concurrency parallel calls of run and returns the snap
+// token of the last batch. It runs alone after the others finished, so that its token covers every batch.
+func runBatches(ctx context.Context, n, concurrency int, run func(ctx context.Context, i int) (string, error)) (string, error) {
+	if n == 0 {
+		return "", nil
+	}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(max(concurrency, 1))
+	for i := 0; i < n-1; i++ {
+		i := i
+		group.Go(func() error {
+			_, err := run(groupCtx, i)
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return "", err
+	}
+	return run(ctx, n-1)
+}
+
+// batch returns the items of the batch starting at start, nil when start is past the end
+func batch[T any](items []T, start, size int) []T {
+	if start >= len(items) {
+		return nil
+	}
+	return items[start:min(start+size, len(items))]
+}
@@ -10,9 +10,9 @@ import (
 )
 
 func Client() v1.DataClient {
-	c, err := client.New(config.CliConfig.PermifyURL)
+	c, err := client.New(config.CliConfig)
 	if err != nil {
-		log.Error("Error initializing permify client. Check the configuration or rerun `permify configure`")
+		log.Error("Error initializing permify client. Check the configuration or rerun `permctl configure`", "error", err)
 		os.Exit(-1)	
 	}
 	return c.Data
@@ -19,9 +19,13 @@ func New() *cobra.Command {
 	}
 	writeCmd := WriteCmd{"write"}
 	readCmd := ReadCmd{"read"}
+	deleteCmd := DeleteCmd{"delete"}
+	syncCmd := SyncCmd{"sync"}
 
 	dataCmd.AddCommand(writeCmd.Cmd())
 	dataCmd.AddCommand(readCmd.Cmd())
+	dataCmd.AddCommand(deleteCmd.Cmd())
+	dataCmd.AddCommand(syncCmd.Cmd())
 
 	return dataCmd
 }
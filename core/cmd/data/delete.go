@@ -0,0 +1,149 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// previewLimit is the number of matching tuples and attributes listed before confirming a delete
+const previewLimit = 20
+
+// DeleteCmd - implements data delete api
+type DeleteCmd struct {
+	Command string
+}
+
+// Cmd - delete command
+func (dc *DeleteCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   dc.Command,
+		Short: "run delete request",
+		Run:   dc.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addTupleFilterFlags(cmd)
+	cmd.Flags().StringSliceP("attribute", "a", nil, "attributes of the filtered entities to delete. Can be repeated")
+	cmd.Flags().BoolP("yes", "y", false, "delete without asking for confirmation")
+	utils.AddSaveSnapTokenFlag(cmd)
+	return cmd
+}
+
+func (dc *DeleteCmd) Run(cmd *cobra.Command, args []string) {
+	tupleFilter, err := tupleFilterFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	attributes, _ := cmd.Flags().GetStringSlice("attribute")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	attributeFilter := &v1.AttributeFilter{}
+	if len(attributes) > 0 {
+		attributeFilter.Entity = tupleFilter.Entity
+		attributeFilter.Attributes = attributes
+		if tupleFilter.Relation == "" && tupleFilter.Subject == nil {
+			tupleFilter = &v1.TupleFilter{}
+		}
+	}
+	if isTupleFilterEmpty(tupleFilter) && len(attributes) == 0 {
+		log.Error("at least one of entity, relation, subject or attribute filters is required")
+		os.Exit(1)
+	}
+
+	dataClient := Client()
+	ctx := context.Background()
+
+	tupleCount, attributeCount, err := previewDelete(ctx, dataClient, tupleFilter, attributeFilter)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	if tupleCount == 0 && attributeCount == 0 {
+		log.Info("no matching tuples or attributes found")
+		return
+	}
+
+	if !yes {
+		confirmed, err := tui.BoolPrompt(fmt.Sprintf("Delete %d tuples and %d attributes?", tupleCount, attributeCount), "n")
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		if !confirmed {
+			log.Error("delete cancelled")
+			os.Exit(1)
+		}
+	}
+
+	deleteRequest := &v1.DataDeleteRequest{
+		TenantId:        config.CliConfig.Tenant,
+		TupleFilter:     tupleFilter,
+		AttributeFilter: attributeFilter,
+	}
+	deleteResponse, err := dataClient.Delete(ctx, deleteRequest)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	printer.Print(deleteResponse)
+	utils.SaveSnapToken(cmd, deleteResponse.SnapToken)
+}
+
+// previewDelete lists the first matching tuples and attributes on stderr and counts all of them
+func previewDelete(ctx context.Context, dataClient v1.DataClient, tupleFilter *v1.TupleFilter, attributeFilter *v1.AttributeFilter) (int, int, error) {
+	tupleCount := 0
+	if !isTupleFilterEmpty(tupleFilter) {
+		err := ReadAllRelationships(ctx, dataClient, &v1.RelationshipReadRequest{
+			TenantId: config.CliConfig.Tenant,
+			Metadata: &v1.RelationshipReadRequestMetadata{},
+			Filter:   tupleFilter,
+		}, func(response *v1.RelationshipReadResponse) error {
+			for _, tuple := range response.Tuples {
+				if tupleCount < previewLimit {
+					fmt.Fprintln(os.Stderr, tui.Warning("- "+utils.TupleToString(tuple)))
+				}
+				tupleCount++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	attributeCount := 0
+	if len(attributeFilter.Attributes) > 0 {
+		err := ReadAllAttributes(ctx, dataClient, &v1.AttributeReadRequest{
+			TenantId: config.CliConfig.Tenant,
+			Metadata: &v1.AttributeReadRequestMetadata{},
+			Filter:   attributeFilter,
+		}, func(response *v1.AttributeReadResponse) error {
+			for _, attribute := range response.Attributes {
+				if attributeCount < previewLimit {
+					fmt.Fprintln(os.Stderr, tui.Warning(fmt.Sprintf("- %s$%s", utils.EntityToString(attribute.Entity), attribute.Attribute)))
+				}
+				attributeCount++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if hidden := tupleCount + attributeCount - min(tupleCount, previewLimit) - min(attributeCount, previewLimit); hidden > 0 {
+		fmt.Fprintf(os.Stderr, "... and %d more\n", hidden)
+	}
+	return tupleCount, attributeCount, nil
+}
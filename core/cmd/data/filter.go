@@ -0,0 +1,88 @@
+package data
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+var (
+	entityFilterRegex  = regexp.MustCompile(`^([\w\d]+)(?::([\w\d,]+))?$`)
+	subjectFilterRegex = regexp.MustCompile(`^([\w\d]+)(?::([\w\d,]+))?(?:#([\w\d]+))?$`)
+)
+
+// addTupleFilterFlags registers the flags used to select relationships
+func addTupleFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("entity", "e", "", "entity filter specified as - <type>:<id> (ids are optional and may be comma separated)")
+	cmd.Flags().StringP("relation", "r", "", "relation filter")
+	cmd.Flags().StringP("subject", "s", "", "subject filter specified as - <type>:<id>#relation (id and relation are optional)")
+}
+
+// tupleFilterFromFlags builds the relationship filter from the flags added by addTupleFilterFlags
+func tupleFilterFromFlags(cmd *cobra.Command) (*v1.TupleFilter, error) {
+	entity, _ := cmd.Flags().GetString("entity")
+	relation, _ := cmd.Flags().GetString("relation")
+	subject, _ := cmd.Flags().GetString("subject")
+
+	filter := &v1.TupleFilter{
+		Relation: relation,
+	}
+	if entity != "" {
+		entityFilter, err := parseEntityFilter(entity)
+		if err != nil {
+			return nil, err
+		}
+		filter.Entity = entityFilter
+	}
+	if subject != "" {
+		subjectFilter, err := parseSubjectFilter(subject)
+		if err != nil {
+			return nil, err
+		}
+		filter.Subject = subjectFilter
+	}
+	return filter, nil
+}
+
+// isTupleFilterEmpty reports whether the filter would match every relationship
+func isTupleFilterEmpty(filter *v1.TupleFilter) bool {
+	return filter.GetEntity().GetType() == "" && len(filter.GetEntity().GetIds()) == 0 &&
+		filter.GetRelation() == "" &&
+		filter.GetSubject().GetType() == "" && len(filter.GetSubject().GetIds()) == 0 && filter.GetSubject().GetRelation() == ""
+}
+
+// parseEntityFilter parses <type>:<id>,<id> where the ids are optional
+func parseEntityFilter(entityStr string) (*v1.EntityFilter, error) {
+	match := entityFilterRegex.FindStringSubmatch(entityStr)
+	if match == nil {
+		return nil, errors.New("Entity filter should match pattern <type>:<id> (ids are optional and may be comma separated)")
+	}
+	return &v1.EntityFilter{
+		Type: match[1],
+		Ids:  splitIds(match[2]),
+	}, nil
+}
+
+// parseSubjectFilter parses <type>:<id>,<id>#relation where the ids and relation are optional
+func parseSubjectFilter(subjectStr string) (*v1.SubjectFilter, error) {
+	match := subjectFilterRegex.FindStringSubmatch(subjectStr)
+	if match == nil {
+		return nil, errors.New("Subject filter should match pattern <type>:<id>#relation (id and relation are optional)")
+	}
+	return &v1.SubjectFilter{
+		Type:     match[1],
+		Ids:      splitIds(match[2]),
+		Relation: match[3],
+	}, nil
+}
+
+func splitIds(ids string) []string {
+	if ids == "" {
+		return nil
+	}
+	return strings.Split(ids, ",")
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// DefaultPageSize is the page size used when all pages of a read are requested
+const DefaultPageSize = 100
+
+// ReadAllRelationships calls fn for every page of relationships matching the filter,
+// following the continuous tokens until the last page
+func ReadAllRelationships(ctx context.Context, dataClient v1.DataClient, request *v1.RelationshipReadRequest, fn func(*v1.RelationshipReadResponse) error) error {
+	if request.PageSize == 0 {
+		request.PageSize = DefaultPageSize
+	}
+	for {
+		response, err := dataClient.ReadRelationships(ctx, request)
+		if err != nil {
+			return err
+		}
+		err = fn(response)
+		if err != nil {
+			return err
+		}
+		if response.ContinuousToken == "" || len(response.Tuples) == 0 {
+			return nil
+		}
+		request.ContinuousToken = response.ContinuousToken
+	}
+}
+
+// ReadAllAttributes calls fn for every page of attributes matching the filter,
+// following the continuous tokens until the last page
+func ReadAllAttributes(ctx context.Context, dataClient v1.DataClient, request *v1.AttributeReadRequest, fn func(*v1.AttributeReadResponse) error) error {
+	if request.PageSize == 0 {
+		request.PageSize = DefaultPageSize
+	}
+	for {
+		response, err := dataClient.ReadAttributes(ctx, request)
+		if err != nil {
+			return err
+		}
+		err = fn(response)
+		if err != nil {
+			return err
+		}
+		if response.ContinuousToken == "" || len(response.Attributes) == 0 {
+			return nil
+		}
+		request.ContinuousToken = response.ContinuousToken
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
-	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
 )
@@ -47,75 +47,67 @@ func (rr *ReadRelationsCmd) Cmd() *cobra.Command {
 		Run: rr.Run,
 		Args: cobra.NoArgs,
 	}
-	cmd.Flags().StringP("entity", "e", "", "entity filter specified as - <type>:<id>")
-	cmd.Flags().StringP("relation", "r", "", "relation filter")
-	cmd.Flags().StringP("subject", "s", "", "subject filter specified as - <type>:<id>#relation (relation is optional)")
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addTupleFilterFlags(cmd)
+	addPageFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
 	return cmd
 }
 
 func (rr *ReadRelationsCmd) Run(cmd *cobra.Command, args []string) {
-	entity, _ := cmd.Flags().GetString("entity")
-	if entity == "" {
-		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
-		if err != nil {
-			log.Error(err.Error())
-			os.Exit(1)
-		}
-		entity = newEntity
+	filter, err := tupleFilterFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
 	}
-	parsedEntity, err := utils.ParseEntity(entity)
+	pageSize, _ := cmd.Flags().GetUint32("page-size")
+	continuousToken, _ := cmd.Flags().GetString("continuous-token")
+	all, _ := cmd.Flags().GetBool("all")
+	snapToken, err := utils.SnapToken(cmd)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
 
-	relation, _ := cmd.Flags().GetString("relation")
-	if relation == "" {
-		newRelation, err := tui.StringPrompt("Enter relation", "", "")
-		if err != nil {
-			log.Error(err.Error())
-			os.Exit(1)
-		}
-		if newRelation== "" {
-			log.Error("relation must not be empty")
-			os.Exit(1)
-		}
-		relation = newRelation
+	dataClient := Client()
+	relationsRequest := &v1.RelationshipReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.RelationshipReadRequestMetadata{
+			SnapToken: snapToken,
+		},
+		Filter: filter,
+		PageSize: pageSize,
+		ContinuousToken: continuousToken,
 	}
-
-	subject, _ := cmd.Flags().GetString("subject")
-	if subject == "" {
-		newSubject, err := tui.StringPrompt("Enter subject string (relation is optional)", "<type>:<id>#<relation>", "")
+	if all {
+		// formats printing a single document need every page first
+		allResponse := &v1.RelationshipReadResponse{}
+		err = ReadAllRelationships(context.Background(), dataClient, relationsRequest, func(response *v1.RelationshipReadResponse) error {
+			if printer.Streaming() {
+				response.ContinuousToken = ""
+				printer.Print(response)
+				return nil
+			}
+			allResponse.Tuples = append(allResponse.Tuples, response.Tuples...)
+			return nil
+		})
 		if err != nil {
 			log.Error(err.Error())
 			os.Exit(1)
 		}
-		subject = newSubject 
+		if !printer.Streaming() {
+			printer.Print(allResponse)
+		}
+		return
 	}
-	parsedSubject, err := utils.ParseSubject(subject)
 
-	dataClient := Client()
-	relationsRequest := &v1.RelationshipReadRequest{
-		TenantId: config.CliConfig.Tenant,
-		Metadata: &v1.RelationshipReadRequestMetadata{},
-		Filter: &v1.TupleFilter{
-			Entity: &v1.EntityFilter{
-				Type: parsedEntity.Type,
-				Ids: []string{parsedEntity.Id},
-			},
-			Relation: relation,
-			Subject: &v1.SubjectFilter{
-				Type: parsedSubject.Type,
-				Ids: []string{parsedSubject.Id},
-			},
-		},
-	}
 	relationResponse, err := dataClient.ReadRelationships(context.Background(), relationsRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(relationResponse)
+	printer.Print(relationResponse)
+	logNextPage(relationResponse.ContinuousToken, len(relationResponse.Tuples))
 }
 
 type ReadAttributesCmd struct {
@@ -125,61 +117,92 @@ type ReadAttributesCmd struct {
 func (ra *ReadAttributesCmd) Cmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: ra.Command,
-		Short: "run lookup subject request",
+		Short: "run read attributes request",
 		Run: ra.Run,
 		Args: cobra.NoArgs,
 	}
-	cmd.Flags().StringP("entity", "e", "", "entity filter specified as - <type>:<id>")
-	cmd.Flags().StringP("relation", "r", "", "relation filter")
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().StringP("entity", "e", "", "entity filter specified as - <type>:<id> (ids are optional and may be comma separated)")
+	cmd.Flags().StringSliceP("attribute", "a", nil, "attribute filter. Can be repeated")
+	addPageFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
 	return cmd
 }
 
 func (ra *ReadAttributesCmd) Run(cmd *cobra.Command, args []string) {
+	filter := &v1.AttributeFilter{}
 	entity, _ := cmd.Flags().GetString("entity")
-	if entity == "" {
-		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
+	if entity != "" {
+		entityFilter, err := parseEntityFilter(entity)
 		if err != nil {
 			log.Error(err.Error())
 			os.Exit(1)
 		}
-		entity = newEntity
+		filter.Entity = entityFilter
 	}
-	parsedEntity, err := utils.ParseEntity(entity)
+	filter.Attributes, _ = cmd.Flags().GetStringSlice("attribute")
+	pageSize, _ := cmd.Flags().GetUint32("page-size")
+	continuousToken, _ := cmd.Flags().GetString("continuous-token")
+	all, _ := cmd.Flags().GetBool("all")
+	snapToken, err := utils.SnapToken(cmd)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
 
-	attribute, _ := cmd.Flags().GetString("attribute")
-	if attribute == "" {
-		newAttribute, err := tui.StringPrompt("Enter attribute", "", "")
+	dataClient := Client()
+	attributeRequest := &v1.AttributeReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.AttributeReadRequestMetadata{
+			SnapToken: snapToken,
+		},
+		Filter: filter,
+		PageSize: pageSize,
+		ContinuousToken: continuousToken,
+	}
+	if all {
+		// formats printing a single document need every page first
+		allResponse := &v1.AttributeReadResponse{}
+		err = ReadAllAttributes(context.Background(), dataClient, attributeRequest, func(response *v1.AttributeReadResponse) error {
+			if printer.Streaming() {
+				response.ContinuousToken = ""
+				printer.Print(response)
+				return nil
+			}
+			allResponse.Attributes = append(allResponse.Attributes, response.Attributes...)
+			return nil
+		})
 		if err != nil {
 			log.Error(err.Error())
 			os.Exit(1)
 		}
-		if newAttribute == "" {
-			log.Error("attribute must not be empty")
-			os.Exit(1)
+		if !printer.Streaming() {
+			printer.Print(allResponse)
 		}
-		attribute = newAttribute
+		return
 	}
 
-	dataClient := Client()
-	attributeRequest := &v1.AttributeReadRequest{
-		TenantId: config.CliConfig.Tenant,
-		Metadata: &v1.AttributeReadRequestMetadata{},
-		Filter: &v1.AttributeFilter{
-			Entity: &v1.EntityFilter{
-				Type: parsedEntity.Type,
-				Ids: []string{parsedEntity.Id},
-			},
-			Attributes: []string{attribute},
-		},
-	}
 	attributeResponse, err := dataClient.ReadAttributes(context.Background(), attributeRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(attributeResponse)
-}
\ No newline at end of file
+	printer.Print(attributeResponse)
+	logNextPage(attributeResponse.ContinuousToken, len(attributeResponse.Attributes))
+}
+
+// addPageFlags registers the pagination flags of read commands
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint32("page-size", 0, "number of results per page. Uses the server default when not set")
+	cmd.Flags().String("continuous-token", "", "continuous token returned by the previous page")
+	cmd.Flags().Bool("all", false, "follow the continuous tokens and print every page as it arrives. json, yaml, table and csv are printed once after the last page")
+	cmd.MarkFlagsMutuallyExclusive("all", "continuous-token")
+}
+
+// logNextPage tells the user how to fetch the next page when there are more results
+func logNextPage(continuousToken string, count int) {
+	if continuousToken == "" || count == 0 {
+		return
+	}
+	log.Info("more results available, pass --continuous-token or --all to read them", "continuous_token", continuousToken)
+}
@@ -0,0 +1,339 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// SyncCmd - reconciles the tuples of a tenant against a desired state file
+type SyncCmd struct {
+	Command string
+}
+
+// SyncSummary is printed after a sync
+type SyncSummary struct {
+	Added     int  `json:"added"`
+	Removed   int  `json:"removed"`
+	Unchanged int  `json:"unchanged"`
+	Applied   bool `json:"applied"`
+	// SnapToken is the snap token of the last write or delete of an applied sync
+	SnapToken string `json:"snap_token,omitempty"`
+}
+
+// syncPlan holds the changes needed to reach the desired tuples
+type syncPlan struct {
+	add       []*v1.Tuple
+	remove    []*v1.Tuple
+	unchanged []*v1.Tuple
+}
+
+// Cmd - sync command
+func (sc *SyncCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   sc.Command,
+		Short: "reconcile tuples against a desired state file",
+		Run:   sc.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addFileFlags(cmd)
+	cmd.Flags().Bool("prune", false, "delete tuples that are not in the file")
+	cmd.Flags().StringSlice("scope", nil, "entity types to reconcile. Defaults to the entity types found in the file")
+	cmd.Flags().Bool("dry-run", false, "print the plan without applying it")
+	cmd.Flags().BoolP("yes", "y", false, "apply the plan without asking for confirmation")
+	utils.AddSaveSnapTokenFlag(cmd)
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (sc *SyncCmd) Run(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	format, _ := cmd.Flags().GetString("format")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	prune, _ := cmd.Flags().GetBool("prune")
+	scope, _ := cmd.Flags().GetStringSlice("scope")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+
+	desired, err := datafile.Read(file, datafile.Format(format))
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	if len(desired.Attributes) > 0 {
+		log.Warn("sync reconciles tuples only, skipping attributes found in file", "attributes", len(desired.Attributes))
+	}
+	if len(scope) == 0 {
+		scope = tupleEntityTypes(desired.Tuples)
+	}
+	if len(scope) == 0 {
+		log.Error("no tuples found in file and no --scope given", "file", file)
+		os.Exit(1)
+	}
+
+	dataClient := Client()
+	ctx := context.Background()
+	plan, err := planSync(ctx, dataClient, desired.Tuples, scope)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	if !prune {
+		plan.unchanged = append(plan.unchanged, plan.remove...)
+		plan.remove = nil
+	}
+	printPlan(plan, prune)
+
+	summary := SyncSummary{
+		Added:     len(plan.add),
+		Removed:   len(plan.remove),
+		Unchanged: len(plan.unchanged),
+	}
+	if dryRun || (len(plan.add) == 0 && len(plan.remove) == 0) {
+		printer.Print(summary)
+		return
+	}
+
+	if !yes {
+		confirmed, err := tui.BoolPrompt(fmt.Sprintf("Add %d and remove %d tuples?", len(plan.add), len(plan.remove)), "n")
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		if !confirmed {
+			log.Error("sync cancelled")
+			os.Exit(1)
+		}
+	}
+
+	summary.SnapToken, err = applySync(ctx, dataClient, plan, BulkWriteOptions{
+		TenantID:      config.CliConfig.Tenant,
+		SchemaVersion: schemaVersion,
+		BatchSize:     batchSize,
+		Concurrency:   concurrency,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	summary.Applied = true
+	printer.Print(summary)
+	utils.SaveSnapToken(cmd, summary.SnapToken)
+}
+
+// planSync reads the current tuples of the scoped entity types and diffs them against the desired tuples
+func planSync(ctx context.Context, dataClient v1.DataClient, desired []*v1.Tuple, scope []string) (*syncPlan, error) {
+	wanted := map[string]*v1.Tuple{}
+	inScope := map[string]bool{}
+	for _, entityType := range scope {
+		inScope[entityType] = true
+	}
+	for _, tuple := range desired {
+		if !inScope[tuple.GetEntity().GetType()] {
+			return nil, fmt.Errorf("tuple %s is outside of the sync scope", utils.TupleToString(tuple))
+		}
+		wanted[utils.TupleToString(tuple)] = tuple
+	}
+
+	plan := &syncPlan{}
+	for _, entityType := range scope {
+		err := ReadAllRelationships(ctx, dataClient, &v1.RelationshipReadRequest{
+			TenantId: config.CliConfig.Tenant,
+			Metadata: &v1.RelationshipReadRequestMetadata{},
+			Filter: &v1.TupleFilter{
+				Entity: &v1.EntityFilter{Type: entityType},
+			},
+		}, func(response *v1.RelationshipReadResponse) error {
+			for _, tuple := range response.Tuples {
+				key := utils.TupleToString(tuple)
+				if _, ok := wanted[key]; ok {
+					plan.unchanged = append(plan.unchanged, tuple)
+					delete(wanted, key)
+					continue
+				}
+				plan.remove = append(plan.remove, tuple)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, tuple := range wanted {
+		plan.add = append(plan.add, tuple)
+	}
+
+	sortTuples(plan.add)
+	sortTuples(plan.remove)
+	return plan, nil
+}
+
+// printPlan lists the planned changes on stderr
+func printPlan(plan *syncPlan, prune bool) {
+	for _, tuple := range plan.add {
+		fmt.Fprintln(os.Stderr, tui.Blue("+ "+utils.TupleToString(tuple)))
+	}
+	for _, tuple := range plan.remove {
+		fmt.Fprintln(os.Stderr, tui.Warning("- "+utils.TupleToString(tuple)))
+	}
+	fmt.Fprintf(os.Stderr, "\nPlan: %d to add, %d to remove, %d unchanged.\n", len(plan.add), len(plan.remove), len(plan.unchanged))
+	if !prune {
+		fmt.Fprintln(os.Stderr, "Tuples missing from the file are kept, use --prune to remove them.")
+	}
+}
+
+// applySync writes the added tuples and then deletes the removed ones, so that a failed sync
+// leaves tuples behind rather than missing ones. The removed tuples are deleted in batches of
+// the tuples of an entity relation, up to BatchSize subjects each.
+// Deleting a tuple whose subject has no relation also deletes the tuples of the same
+// subject with a relation, those are written again when they are part of the desired state.
+// It returns the snap token of the last write or delete, which covers all of them.
+func applySync(ctx context.Context, dataClient v1.DataClient, plan *syncPlan, opts BulkWriteOptions) (string, error) {
+	var snapToken string
+	if len(plan.add) > 0 {
+		summary, err := BulkWrite(ctx, dataClient, &datafile.Data{Tuples: plan.add}, opts)
+		if err != nil {
+			return "", err
+		}
+		snapToken = summary.SnapToken
+	}
+	if len(plan.remove) == 0 {
+		return snapToken, nil
+	}
+
+	filters := deleteFilters(plan.remove, opts.BatchSize)
+	progress := tui.NewProgress("removing tuples", len(plan.remove))
+	snapToken, err := runBatches(ctx, len(filters), opts.Concurrency, func(ctx context.Context, i int) (string, error) {
+		filter := filters[i]
+		response, err := dataClient.DeleteRelationships(ctx, &v1.RelationshipDeleteRequest{
+			TenantId: opts.TenantID,
+			Filter:   filter,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to remove %d tuples of %s:%s#%s: %w", len(filter.Subject.Ids), filter.Entity.Type, filter.Entity.Ids[0], filter.Relation, err)
+		}
+		progress.Add(len(filter.Subject.Ids))
+		return response.SnapToken, nil
+	})
+	progress.Finish()
+	if err != nil {
+		return "", err
+	}
+
+	removed := removedSubjects(plan.remove)
+	rewrite := []*v1.Tuple{}
+	for _, tuple := range append(slices.Clone(plan.unchanged), plan.add...) {
+		if tuple.GetSubject().GetRelation() != "" && removed[subjectKeyOf(tuple)] {
+			rewrite = append(rewrite, tuple)
+		}
+	}
+	if len(rewrite) == 0 {
+		return snapToken, nil
+	}
+	summary, err := BulkWrite(ctx, dataClient, &datafile.Data{Tuples: rewrite}, opts)
+	if err != nil {
+		return "", err
+	}
+	return summary.SnapToken, nil
+}
+
+// deleteFilters groups the tuples by entity, relation, subject type and subject relation into
+// filters matching up to batchSize subject ids each
+func deleteFilters(tuples []*v1.Tuple, batchSize int) []*v1.TupleFilter {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	filters := []*v1.TupleFilter{}
+	groups := map[string]*v1.TupleFilter{}
+	for _, tuple := range tuples {
+		key := fmt.Sprintf("%s#%s@%s#%s", utils.EntityToString(tuple.GetEntity()), tuple.GetRelation(), tuple.GetSubject().GetType(), tuple.GetSubject().GetRelation())
+		filter, ok := groups[key]
+		if !ok || len(filter.Subject.Ids) >= batchSize {
+			filter = exactTupleFilter(tuple)
+			filter.Subject.Ids = nil
+			groups[key] = filter
+			filters = append(filters, filter)
+		}
+		filter.Subject.Ids = append(filter.Subject.Ids, tuple.GetSubject().GetId())
+	}
+	return filters
+}
+
+// exactTupleFilter returns a filter matching the given tuple
+func exactTupleFilter(tuple *v1.Tuple) *v1.TupleFilter {
+	return &v1.TupleFilter{
+		Entity: &v1.EntityFilter{
+			Type: tuple.GetEntity().GetType(),
+			Ids:  []string{tuple.GetEntity().GetId()},
+		},
+		Relation: tuple.GetRelation(),
+		Subject: &v1.SubjectFilter{
+			Type:     tuple.GetSubject().GetType(),
+			Ids:      []string{tuple.GetSubject().GetId()},
+			Relation: tuple.GetSubject().GetRelation(),
+		},
+	}
+}
+
+// subjectKey identifies the tuples a delete filter without a subject relation matches
+type subjectKey struct {
+	entityType, entityId, relation, subjectType, subjectId string
+}
+
+func subjectKeyOf(tuple *v1.Tuple) subjectKey {
+	return subjectKey{
+		entityType:  tuple.GetEntity().GetType(),
+		entityId:    tuple.GetEntity().GetId(),
+		relation:    tuple.GetRelation(),
+		subjectType: tuple.GetSubject().GetType(),
+		subjectId:   tuple.GetSubject().GetId(),
+	}
+}
+
+// removedSubjects returns the keys of the removed tuples without a subject relation, deleting
+// them also deletes the tuples of the same key with any subject relation
+func removedSubjects(removed []*v1.Tuple) map[subjectKey]bool {
+	keys := map[subjectKey]bool{}
+	for _, tuple := range removed {
+		if tuple.GetSubject().GetRelation() == "" {
+			keys[subjectKeyOf(tuple)] = true
+		}
+	}
+	return keys
+}
+
+// tupleEntityTypes returns the sorted entity types of the tuples
+func tupleEntityTypes(tuples []*v1.Tuple) []string {
+	seen := map[string]bool{}
+	types := []string{}
+	for _, tuple := range tuples {
+		if !seen[tuple.GetEntity().GetType()] {
+			seen[tuple.GetEntity().GetType()] = true
+			types = append(types, tuple.GetEntity().GetType())
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// sortTuples orders tuples by their text notation
+func sortTuples(tuples []*v1.Tuple) {
+	sort.Slice(tuples, func(i, j int) bool {
+		return utils.TupleToString(tuples[i]) < utils.TupleToString(tuples[j])
+	})
+}
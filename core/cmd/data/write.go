@@ -2,12 +2,17 @@ package data
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/tui"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
@@ -30,10 +35,32 @@ func (wc *WriteCmd) Cmd() *cobra.Command {
 	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
 	cmd.Flags().StringP("relation", "r", "", "relation between entity and subject")
 	cmd.Flags().StringP("subject", "s", "", "subject identifier specified as - <type>:<id>#relation (relation is optional)")
+	addFileFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("file", "entity")
+	cmd.MarkFlagsMutuallyExclusive("file", "relation")
+	cmd.MarkFlagsMutuallyExclusive("file", "subject")
+	utils.AddSaveSnapTokenFlag(cmd)
+
+	attributeCmd := WriteAttributeCmd{"attribute"}
+	cmd.AddCommand(attributeCmd.Cmd())
 	return cmd
 }
 
+// addFileFlags registers the flags used to write data files in bulk
+func addFileFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", "", "json, yaml, csv or text file with tuples and attributes to write. Use - to read from stdin")
+	cmd.Flags().String("format", "", "format of the data file (json|yaml|csv|text). Detected from the file by default")
+	cmd.Flags().Int("batch-size", DefaultBatchSize, "number of tuples and attributes sent in a single write request")
+	cmd.Flags().Int("concurrency", 4, "number of write requests sent in parallel")
+}
+
 func (wc *WriteCmd) Run(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		writeFile(cmd, file, false)
+		return
+	}
+
 	entity, _ := cmd.Flags().GetString("entity")
 	if entity == "" {
 		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
@@ -73,6 +100,10 @@ func (wc *WriteCmd) Run(cmd *cobra.Command, args []string) {
 		subject = newSubject 
 	}
 	parsedSubject, err := utils.ParseSubject(subject)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
 
 	schemaVersion, _ := cmd.Flags().GetString("schema")
 	dataClient := Client()
@@ -92,5 +123,140 @@ func (wc *WriteCmd) Run(cmd *cobra.Command, args []string) {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(writeResponse)
+	printer.Print(writeResponse)
+	utils.SaveSnapToken(cmd, writeResponse.SnapToken)
+}
+
+// writeFile writes all tuples and attributes of a data file in batches.
+// Tuples in the file are skipped when onlyAttributes is set.
+func writeFile(cmd *cobra.Command, file string, onlyAttributes bool) {
+	format, _ := cmd.Flags().GetString("format")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+
+	data, err := datafile.Read(file, datafile.Format(format))
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	if onlyAttributes && len(data.Tuples) > 0 {
+		log.Warn("skipping tuples found in attribute file", "tuples", len(data.Tuples))
+		data.Tuples = nil
+	}
+	if len(data.Tuples) == 0 && len(data.Attributes) == 0 {
+		log.Error("no tuples or attributes found in file", "file", file)
+		os.Exit(1)
+	}
+
+	summary, err := BulkWrite(context.Background(), Client(), data, BulkWriteOptions{
+		TenantID:      config.CliConfig.Tenant,
+		SchemaVersion: schemaVersion,
+		BatchSize:     batchSize,
+		Concurrency:   concurrency,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	printer.Print(summary)
+	utils.SaveSnapToken(cmd, summary.SnapToken)
+}
+
+// WriteAttributeCmd - implements data write api for attributes
+type WriteAttributeCmd struct {
+	Command string
+}
+
+// Cmd - write attribute command
+func (wa *WriteAttributeCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   wa.Command,
+		Short: "run write attribute request",
+		Run:   wa.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
+	cmd.Flags().StringP("attribute", "a", "", "attribute name")
+	cmd.Flags().String("value", "", "attribute value. Array values are comma separated")
+	cmd.Flags().StringP("type", "t", "", fmt.Sprintf("attribute value type (%s)", strings.Join(dsl.AttributeTypes, "|")))
+	addFileFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("file", "entity")
+	cmd.MarkFlagsMutuallyExclusive("file", "attribute")
+	cmd.MarkFlagsMutuallyExclusive("file", "value")
+	cmd.MarkFlagsMutuallyExclusive("file", "type")
+	utils.AddSaveSnapTokenFlag(cmd)
+	return cmd
+}
+
+func (wa *WriteAttributeCmd) Run(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		writeFile(cmd, file, true)
+		return
+	}
+
+	entity, _ := cmd.Flags().GetString("entity")
+	if entity == "" {
+		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		entity = newEntity
+	}
+
+	attribute, _ := cmd.Flags().GetString("attribute")
+	if attribute == "" {
+		newAttribute, err := tui.StringPrompt("Enter attribute", "", "")
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		attribute = newAttribute
+	}
+
+	valueType, _ := cmd.Flags().GetString("type")
+	if valueType == "" {
+		newValueType, err := tui.Choice("Select attribute type", dsl.AttributeTypes)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		valueType = newValueType
+	}
+
+	value, _ := cmd.Flags().GetString("value")
+	if !cmd.Flags().Changed("value") {
+		newValue, err := tui.StringPrompt("Enter attribute value (array values are comma separated)", "", "")
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		value = newValue
+	}
+
+	parsedAttribute, err := utils.NewAttribute(entity, attribute, valueType, value)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+	dataClient := Client()
+	writeRequest := &v1.DataWriteRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.DataWriteRequestMetadata{
+			SchemaVersion: schemaVersion,
+		},
+		Attributes: []*v1.Attribute{parsedAttribute},
+	}
+	writeResponse, err := dataClient.Write(context.Background(), writeRequest)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	printer.Print(writeResponse)
+	utils.SaveSnapToken(cmd, writeResponse.SnapToken)
 }
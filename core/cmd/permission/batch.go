@@ -0,0 +1,232 @@
+package permission
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// BatchCheck is a single check of a batch
+type BatchCheck struct {
+	Entity     string `yaml:"entity" json:"entity"`
+	Permission string `yaml:"permission" json:"permission"`
+	Subject    string `yaml:"subject" json:"subject"`
+}
+
+// BatchCheckResult is the result of a check of a batch, allowed, denied or error
+type BatchCheckResult struct {
+	BatchCheck
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchCheckResults is printed after a batch check. In matrix mode tables and csv show a row per
+// entity and subject with a column per permission.
+type BatchCheckResults struct {
+	Results []*BatchCheckResult `json:"results"`
+
+	permissions []string
+}
+
+// Headers of the table layout
+func (r *BatchCheckResults) Headers() []string {
+	if r.permissions == nil {
+		return []string{"entity", "permission", "subject", "result", "error"}
+	}
+	return append([]string{"entity", "subject"}, r.permissions...)
+}
+
+// Rows of the table layout
+func (r *BatchCheckResults) Rows() [][]string {
+	rows := [][]string{}
+	if r.permissions == nil {
+		for _, result := range r.Results {
+			rows = append(rows, []string{result.Entity, result.Permission, result.Subject, result.Result, result.Error})
+		}
+		return rows
+	}
+	// matrix results are ordered by entity, subject and permission
+	for i := 0; i < len(r.Results); i += len(r.permissions) {
+		row := []string{r.Results[i].Entity, r.Results[i].Subject}
+		for _, result := range r.Results[i : i+len(r.permissions)] {
+			row = append(row, result.Result)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Count returns the number of results with the given result
+func (r *BatchCheckResults) Count(result string) int {
+	n := 0
+	for _, checkResult := range r.Results {
+		if checkResult.Result == result {
+			n++
+		}
+	}
+	return n
+}
+
+// Results of a batch check
+const (
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultError   = "error"
+)
+
+// readBatchChecks reads checks from a csv file of entity,permission,subject records or a yaml or json
+// list of checks. The format is detected from the extension or, for stdin, from the content.
+func readBatchChecks(path string) ([]*BatchCheck, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []*BatchCheck{}
+	if isCSV(path, content) {
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.FieldsPerRecord = 3
+		reader.TrimLeadingSpace = true
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for i, record := range records {
+			if i == 0 && record[0] == "entity" && record[1] == "permission" && record[2] == "subject" {
+				continue
+			}
+			checks = append(checks, &BatchCheck{Entity: record[0], Permission: record[1], Subject: record[2]})
+		}
+	} else if err := yaml.Unmarshal(content, &checks); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(checks) == 0 {
+		return nil, fmt.Errorf("%s: no checks found", path)
+	}
+	return checks, nil
+}
+
+func isCSV(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return true
+	case ".yaml", ".yml", ".json":
+		return false
+	}
+	trimmed := bytes.TrimSpace(content)
+	return len(trimmed) > 0 && !bytes.ContainsAny(trimmed[:1], "[{-")
+}
+
+// matrixChecks returns the checks of every entity, subject and permission combination
+func matrixChecks(entities, permissions, subjects []string) []*BatchCheck {
+	checks := []*BatchCheck{}
+	for _, entity := range entities {
+		for _, subject := range subjects {
+			for _, permission := range permissions {
+				checks = append(checks, &BatchCheck{Entity: entity, Permission: permission, Subject: subject})
+			}
+		}
+	}
+	return checks
+}
+
+// BatchOptions configures how the checks of a batch are sent
+type BatchOptions struct {
+	SchemaVersion string
+	SnapToken     string
+	Depth         int32
+	Concurrency   int
+	// Rate is the largest number of checks sent per second, 0 sends them as fast as possible
+	Rate float64
+	// Context holds the contextual tuples and attributes sent with every check
+	Context *v1.Context
+}
+
+// runBatch sends the checks with up to Concurrency parallel requests. A failing check does
+// not stop the batch, its error is part of the result.
+func runBatch(ctx context.Context, permissionClient v1.PermissionClient, checks []*BatchCheck, opts BatchOptions) *BatchCheckResults {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	progress := tui.NewProgress("checking", len(checks))
+	defer progress.Finish()
+	results := make([]*BatchCheckResult, len(checks))
+	group := errgroup.Group{}
+	group.SetLimit(opts.Concurrency)
+	for i, check := range checks {
+		i, check := i, check
+		if tick != nil {
+			<-tick
+		}
+		group.Go(func() error {
+			results[i] = runBatchCheck(ctx, permissionClient, check, opts)
+			progress.Add(1)
+			return nil
+		})
+	}
+	group.Wait()
+	return &BatchCheckResults{Results: results}
+}
+
+func runBatchCheck(ctx context.Context, permissionClient v1.PermissionClient, check *BatchCheck, opts BatchOptions) *BatchCheckResult {
+	result := &BatchCheckResult{BatchCheck: *check, Result: ResultError}
+	entity, err := utils.ParseEntity(check.Entity)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	subject, err := utils.ParseSubject(check.Subject)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	checkResponse, err := permissionClient.Check(ctx, &v1.PermissionCheckRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.PermissionCheckRequestMetadata{
+			SchemaVersion: opts.SchemaVersion,
+			SnapToken:     opts.SnapToken,
+			Depth:         opts.Depth,
+		},
+		Entity:     entity,
+		Permission: check.Permission,
+		Subject:    subject,
+		Context:    opts.Context,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Result = ResultDenied
+	if checkResponse.Can == v1.CheckResult_CHECK_RESULT_ALLOWED {
+		result.Result = ResultAllowed
+	}
+	return result
+}
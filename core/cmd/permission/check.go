@@ -3,6 +3,7 @@ package permission
 import (
 	"context"
 	"os"
+	"strconv"
 
 	"github.com/charmbracelet/log"
 
@@ -10,10 +11,18 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/tui"
 	"github.com/Permify/permify-cli/utils"
 )
 
+// Exit codes of the check command when --exit-code or --quiet is set
+const (
+	ExitAllowed = 0
+	ExitDenied  = 1
+	ExitError   = 2
+)
+
 // CheckCmd - implements permission check api
 type CheckCmd struct {
 	Command string
@@ -26,29 +35,64 @@ func (cc *CheckCmd) Cmd() *cobra.Command {
 		Short: "run check request",
 		Run:  cc.Run,
 		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			config.ExitCodeAnnotation:      strconv.Itoa(ExitError),
+			config.ExitCodeFlagsAnnotation: "exit-code,quiet",
+		},
 	}
 	cmd.SetHelpFunc(utils.CmdHelp)
 	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
 	cmd.Flags().StringP("permission", "p", "", "permission to check")
 	cmd.Flags().StringP("subject", "s", "", "subject identifier specified as - <type>:<id>#relation (relation is optional)")
 	cmd.Flags().Int32("depth", 50, "depth of the check must be >= 3. Default: 50")
+	cmd.Flags().Bool("exit-code", false, "exit with 0 when allowed, 1 when denied and 2 on errors")
+	cmd.Flags().BoolP("quiet", "q", false, "do not print the response. Implies --exit-code")
+	cmd.Flags().StringP("file", "f", "", "csv, yaml or json file of checks to run as a batch. Use - to read from stdin")
+	cmd.Flags().StringSlice("entities", nil, "entities of the matrix checked for every permission and subject")
+	cmd.Flags().StringSlice("permissions", nil, "permissions of the matrix")
+	cmd.Flags().StringSlice("subjects", nil, "subjects of the matrix")
+	cmd.Flags().Int("concurrency", 4, "number of checks of a batch sent in parallel")
+	cmd.Flags().Float64("rate", 0, "largest number of checks of a batch sent per second. Default: no limit")
+	addContextFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
+	cmd.MarkFlagsRequiredTogether("entities", "permissions", "subjects")
+	cmd.MarkFlagsMutuallyExclusive("file", "entities")
+	for _, single := range []string{"entity", "permission", "subject"} {
+		cmd.MarkFlagsMutuallyExclusive("file", single)
+		cmd.MarkFlagsMutuallyExclusive("entities", single)
+	}
 	return cmd
 }
 
 func (cc *CheckCmd) Run(cmd *cobra.Command, args []string) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	exitCode, _ := cmd.Flags().GetBool("exit-code")
+	exitCode = exitCode || quiet
+	errorCode := 1
+	if exitCode {
+		errorCode = ExitError
+	}
+
+	file, _ := cmd.Flags().GetString("file")
+	entities, _ := cmd.Flags().GetStringSlice("entities")
+	if file != "" || len(entities) > 0 {
+		runBatchCmd(cmd, quiet, exitCode, errorCode)
+		return
+	}
+
 	entity, _ := cmd.Flags().GetString("entity")
 	if entity == "" {
 		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
 		if err != nil {
 			log.Error(err.Error())
-			os.Exit(1)
+			os.Exit(errorCode)
 		}
 		entity = newEntity
 	}
 	parsedEntity, err := utils.ParseEntity(entity)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(errorCode)
 	}
 
 	permission, _ := cmd.Flags().GetString("permission")
@@ -56,11 +100,11 @@ func (cc *CheckCmd) Run(cmd *cobra.Command, args []string) {
 		newPermission, err := tui.StringPrompt("Enter permission to check", "", "")
 		if err != nil {
 			log.Error(err.Error())
-			os.Exit(1)
+			os.Exit(errorCode)
 		}
 		if newPermission == "" {
 			log.Error("permission must not be empty")
-			os.Exit(1)
+			os.Exit(errorCode)
 		}
 		permission = newPermission
 	}
@@ -70,30 +114,110 @@ func (cc *CheckCmd) Run(cmd *cobra.Command, args []string) {
 		newSubject, err := tui.StringPrompt("Enter subject string (relation is optional)", "<type>:<id>#<relation>", "")
 		if err != nil {
 			log.Error(err.Error())
-			os.Exit(1)
+			os.Exit(errorCode)
 		}
 		subject = newSubject 
 	}
 	parsedSubject, err := utils.ParseSubject(subject)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(errorCode)
+	}
 
 	schemaVersion, _ := cmd.Flags().GetString("schema")
 	depth, _ := cmd.Flags().GetInt32("depth")
 
-	permissionClient := Client()
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(errorCode)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(errorCode)
+	}
+
+	permissionClient := clientOrExit(errorCode)
 	checkRequest := &v1.PermissionCheckRequest{
 		TenantId: config.CliConfig.Tenant,
 		Metadata: &v1.PermissionCheckRequestMetadata{
 			SchemaVersion: schemaVersion,
+			SnapToken: snapToken,
 			Depth: depth,
 		},
 		Entity: parsedEntity,
 		Permission: permission,
 		Subject: parsedSubject,
+		Context: requestContext,
 	}
 	checkResponse, err := permissionClient.Check(context.Background(), checkRequest)
 	if err != nil {
 		log.Error(err.Error())
-		os.Exit(1)
+		os.Exit(errorCode)
+	}
+	if !quiet {
+		printer.Print(checkResponse)
+	}
+	if exitCode && checkResponse.Can != v1.CheckResult_CHECK_RESULT_ALLOWED {
+		os.Exit(ExitDenied)
+	}
+}
+
+// runBatchCmd runs the checks of --file or the matrix of --entities, --permissions and --subjects
+func runBatchCmd(cmd *cobra.Command, quiet, exitCode bool, errorCode int) {
+	file, _ := cmd.Flags().GetString("file")
+	var checks []*BatchCheck
+	matrix := file == ""
+	if matrix {
+		entities, _ := cmd.Flags().GetStringSlice("entities")
+		permissions, _ := cmd.Flags().GetStringSlice("permissions")
+		subjects, _ := cmd.Flags().GetStringSlice("subjects")
+		checks = matrixChecks(entities, permissions, subjects)
+	} else {
+		fileChecks, err := readBatchChecks(file)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(errorCode)
+		}
+		checks = fileChecks
+	}
+
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+	depth, _ := cmd.Flags().GetInt32("depth")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(errorCode)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(errorCode)
+	}
+	results := runBatch(context.Background(), clientOrExit(errorCode), checks, BatchOptions{
+		SchemaVersion: schemaVersion,
+		SnapToken:     snapToken,
+		Depth:         depth,
+		Concurrency:   concurrency,
+		Rate:          rate,
+		Context:       requestContext,
+	})
+	if matrix {
+		results.permissions, _ = cmd.Flags().GetStringSlice("permissions")
+	}
+	if !quiet {
+		printer.Print(results)
+	}
+
+	failed := results.Count(ResultError)
+	if failed > 0 {
+		log.Error("checks failed", "failed", failed, "total", len(results.Results))
+		os.Exit(errorCode)
+	}
+	if exitCode && results.Count(ResultDenied) > 0 {
+		os.Exit(ExitDenied)
 	}
-	utils.PrettyPrint(checkResponse)
 }
@@ -10,10 +10,15 @@ import (
 )
 
 func Client() v1.PermissionClient {
-	c, err := client.New(config.CliConfig.PermifyURL)
+	return clientOrExit(-1)
+}
+
+// clientOrExit returns the permission client, it exits with code when the client can not be initialized
+func clientOrExit(code int) v1.PermissionClient {
+	c, err := client.New(config.CliConfig)
 	if err != nil {
-		log.Error("Error initializing permify client. Check the configuration or rerun `permify configure`")
-		os.Exit(-1)	
+		log.Error("Error initializing permify client. Check the configuration or rerun `permctl configure`", "error", err)
+		os.Exit(code)	
 	}
 	return c.Permission
 }
\ No newline at end of file
@@ -0,0 +1,54 @@
+package permission
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// addContextFlags registers the flags of the contextual tuples and attributes of a request
+func addContextFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("context-tuple", nil, "contextual tuple specified as - <type>:<id>#<relation>@<type>:<id>. Can be repeated")
+	cmd.Flags().StringArray("context-attribute", nil, "contextual attribute specified as - <type>:<id>$<attribute>|<value type>:<value>. Can be repeated")
+	cmd.Flags().String("context-file", "", "json, yaml, csv or text data file of contextual tuples and attributes. Use - to read from stdin")
+}
+
+// contextFromFlags returns the contextual tuples and attributes of the flags, they are evaluated
+// with the request without being written. It returns nil when no context flag is set.
+func contextFromFlags(cmd *cobra.Command) (*v1.Context, error) {
+	tuples, _ := cmd.Flags().GetStringArray("context-tuple")
+	attributes, _ := cmd.Flags().GetStringArray("context-attribute")
+	file, _ := cmd.Flags().GetString("context-file")
+	if len(tuples) == 0 && len(attributes) == 0 && file == "" {
+		return nil, nil
+	}
+
+	requestContext := &v1.Context{}
+	if file != "" {
+		data, err := datafile.Read(file, "")
+		if err != nil {
+			return nil, fmt.Errorf("context file %s: %w", file, err)
+		}
+		requestContext.Tuples = append(requestContext.Tuples, data.Tuples...)
+		requestContext.Attributes = append(requestContext.Attributes, data.Attributes...)
+	}
+	for _, tuple := range tuples {
+		parsedTuple, err := utils.ParseTuple(tuple)
+		if err != nil {
+			return nil, fmt.Errorf("context tuple %s: %w", tuple, err)
+		}
+		requestContext.Tuples = append(requestContext.Tuples, parsedTuple)
+	}
+	for _, attribute := range attributes {
+		parsedAttribute, err := utils.ParseAttribute(attribute)
+		if err != nil {
+			return nil, fmt.Errorf("context attribute %s: %w", attribute, err)
+		}
+		requestContext.Attributes = append(requestContext.Attributes, parsedAttribute)
+	}
+	return requestContext, nil
+}
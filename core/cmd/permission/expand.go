@@ -2,23 +2,25 @@ package permission
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/tui"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
 )
 
-// ExpandCmd - implements permission check api
+// ExpandCmd - implements permission expand api
 type ExpandCmd struct {
 	Command string
 }
 
-// Cmd - check command
+// Cmd - expand command
 func (ec *ExpandCmd) Cmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   ec.Command,
@@ -29,10 +31,19 @@ func (ec *ExpandCmd) Cmd() *cobra.Command {
 	cmd.SetHelpFunc(utils.CmdHelp)
 	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
 	cmd.Flags().StringP("permission", "p", "", "[Optional] permission to check")
+	cmd.Flags().String("format", "tree", "rendering of the expansion (tree|dot|mermaid). Ignored when --output is set")
+	cmd.Flags().Int("collapse", 10, "largest number of subjects shown per relation in the tree, 0 shows all")
+	utils.AddSnapTokenFlags(cmd)
 	return cmd
 }
 
 func (ec *ExpandCmd) Run(cmd *cobra.Command, args []string) {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "tree" && format != "dot" && format != "mermaid" {
+		log.Error("unknown expand format, expected tree, dot or mermaid", "format", format)
+		os.Exit(1)
+	}
+
 	entity, _ := cmd.Flags().GetString("entity")
 	if entity == "" {
 		newEntity, err := tui.StringPrompt("Enter entity string", "<type>:<id>", "")
@@ -50,12 +61,18 @@ func (ec *ExpandCmd) Run(cmd *cobra.Command, args []string) {
 
 	permission, _ := cmd.Flags().GetString("permission")
 	schemaVersion, _ := cmd.Flags().GetString("schema")
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
 
 	permissionClient := Client()
 	expandRequest := &v1.PermissionExpandRequest{
 		TenantId: config.CliConfig.Tenant,
 		Metadata: &v1.PermissionExpandRequestMetadata{
 			SchemaVersion: schemaVersion,
+			SnapToken: snapToken,
 		},
 		Entity: parsedEntity,
 		Permission: permission,
@@ -66,5 +83,19 @@ func (ec *ExpandCmd) Run(cmd *cobra.Command, args []string) {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(expandResponse)
+	if cmd.Flags().Changed("output") {
+		printer.Print(expandResponse)
+		return
+	}
+
+	collapse, _ := cmd.Flags().GetInt("collapse")
+	tree := buildExpandTree(expandResponse.GetTree(), nil, collapse)
+	switch format {
+	case "dot":
+		fmt.Fprint(printer.Out, renderExpandDOT(tree))
+	case "mermaid":
+		fmt.Fprint(printer.Out, renderExpandMermaid(tree))
+	default:
+		fmt.Fprint(printer.Out, renderExpandTree(tree, printer.Color))
+	}
 }
@@ -0,0 +1,589 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/client"
+	"github.com/Permify/permify-cli/core/cmd/data"
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ExplainCmd - explains the result of a permission check
+type ExplainCmd struct {
+	Command string
+}
+
+// Cmd - explain command
+func (ec *ExplainCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   ec.Command,
+		Short: "show why a check is allowed or denied",
+		Run:   ec.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
+	cmd.Flags().StringP("permission", "p", "", "permission to explain")
+	cmd.Flags().StringP("subject", "s", "", "subject identifier specified as - <type>:<id>#relation (relation is optional)")
+	cmd.Flags().Int32("depth", 50, "depth of the check must be >= 3, also limits how deep the trace follows relations. Default: 50")
+	cmd.MarkFlagRequired("entity")
+	cmd.MarkFlagRequired("permission")
+	cmd.MarkFlagRequired("subject")
+	addContextFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
+	return cmd
+}
+
+func (ec *ExplainCmd) Run(cmd *cobra.Command, args []string) {
+	entity, _ := cmd.Flags().GetString("entity")
+	parsedEntity, err := utils.ParseEntity(entity)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	subject, _ := cmd.Flags().GetString("subject")
+	parsedSubject, err := utils.ParseSubject(subject)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	permission, _ := cmd.Flags().GetString("permission")
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+	depth, _ := cmd.Flags().GetInt32("depth")
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	c, err := client.New(config.CliConfig)
+	if err != nil {
+		log.Error("Error initializing permify client. Check the configuration or rerun `permctl configure`", "error", err)
+		os.Exit(-1)
+	}
+	ctx := context.Background()
+	readResponse, err := c.Schema.Read(ctx, &v1.SchemaReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.SchemaReadRequestMetadata{
+			SchemaVersion: schemaVersion,
+		},
+	})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	// the trace does not depend on the parameter order of rules
+	schema, _, err := dsl.FromDefinition(readResponse.Schema)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	checkResponse, err := c.Permission.Check(ctx, &v1.PermissionCheckRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.PermissionCheckRequestMetadata{
+			SchemaVersion: schemaVersion,
+			SnapToken:     snapToken,
+			Depth:         depth,
+		},
+		Entity:     parsedEntity,
+		Permission: permission,
+		Subject:    parsedSubject,
+		Context:    requestContext,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	e := &explainer{
+		ctx:              ctx,
+		permissionClient: c.Permission,
+		dataClient:       c.Data,
+		schemaVersion:    schemaVersion,
+		snapToken:        snapToken,
+		schema:           schema,
+		subject:          parsedSubject,
+		context:          requestContext,
+		maxDepth:         int(depth),
+		visiting:         map[string]bool{},
+		expansions:       map[string]*v1.Expand{},
+	}
+	explanation := &Explanation{
+		Result: ResultDenied,
+		Trace:  e.permission(parsedEntity, permission, 0),
+	}
+	if checkResponse.Can == v1.CheckResult_CHECK_RESULT_ALLOWED {
+		explanation.Result = ResultAllowed
+	}
+	if cmd.Flags().Changed("output") {
+		printer.Print(explanation)
+		return
+	}
+	printExplanation(explanation)
+}
+
+// traceResult is the outcome of a step of the trace
+type traceResult string
+
+const (
+	traceGranted traceResult = "granted"
+	traceMissing traceResult = "missing"
+	// traceUnknown is the result of rules, which are evaluated by permify only, and of the steps depending on them
+	traceUnknown traceResult = "unknown"
+)
+
+// TraceNode is a step of the evaluation of a permission
+type TraceNode struct {
+	Label string `json:"label"`
+	// Expression is the definition of a permission
+	Expression string `json:"expression,omitempty"`
+	// Operator is set for the nodes combining their children, their label is the expression
+	Operator string      `json:"operator,omitempty"`
+	Result   traceResult `json:"result"`
+	Detail   string      `json:"detail,omitempty"`
+	// Excluded is set for the operand of not, its result is the inverse of the result of the operand
+	Excluded bool         `json:"excluded,omitempty"`
+	Children []*TraceNode `json:"children,omitempty"`
+}
+
+// Explanation is the result of the check with the trace of the evaluation
+type Explanation struct {
+	Result string     `json:"result"`
+	Trace  *TraceNode `json:"trace"`
+}
+
+// explainer evaluates permission expressions of the schema for a subject by reading the relationships they follow
+type explainer struct {
+	ctx              context.Context
+	permissionClient v1.PermissionClient
+	dataClient       v1.DataClient
+	schemaVersion    string
+	// snapToken is sent with every request, so the trace reads the same snapshot as the check
+	snapToken string
+	schema    *dsl.Schema
+	subject   *v1.Subject
+	// context holds the contextual tuples and attributes, they are part of the trace like written ones
+	context  *v1.Context
+	maxDepth int
+	// visiting holds the permissions being evaluated, to stop at cycles
+	visiting map[string]bool
+	// expansions caches the expand trees of permissions by <entity>#<permission>
+	expansions map[string]*v1.Expand
+}
+
+// permission explains a relation, attribute or permission of an entity
+func (e *explainer) permission(entity *v1.Entity, name string, depth int) *TraceNode {
+	label := fmt.Sprintf("%s#%s", utils.EntityToString(entity), name)
+	definition := e.schema.Entity(entity.GetType())
+	switch {
+	case definition == nil:
+		return &TraceNode{Label: label, Result: traceUnknown, Detail: "entity " + entity.GetType() + " is not in the schema"}
+	case definition.Relation(name) != nil:
+		return e.relation(entity, name, depth)
+	case definition.Attribute(name) != nil:
+		return e.attribute(entity, name)
+	case definition.Permission(name) == nil:
+		return &TraceNode{Label: label, Result: traceUnknown, Detail: "entity " + entity.GetType() + " has no relation, attribute or permission " + name}
+	case e.visiting[label]:
+		return &TraceNode{Label: label, Result: traceMissing, Detail: "cycle, already being evaluated"}
+	case depth >= e.maxDepth:
+		return &TraceNode{Label: label, Result: traceUnknown, Detail: "depth limit reached"}
+	}
+
+	e.visiting[label] = true
+	defer delete(e.visiting, label)
+	expr := definition.Permission(name).Expr
+	node := e.expr(entity, name, expr, depth+1)
+	if node.Operator != "" {
+		return &TraceNode{Label: label, Expression: expr.String(), Result: node.Result, Children: node.Children}
+	}
+	return &TraceNode{Label: label, Expression: expr.String(), Result: node.Result, Children: []*TraceNode{node}}
+}
+
+// expr explains an expression of the permission of an entity, operands of the same operator are listed together
+func (e *explainer) expr(entity *v1.Entity, permission string, expr dsl.Expr, depth int) *TraceNode {
+	switch expr := expr.(type) {
+	case *dsl.BinaryExpr:
+		node := &TraceNode{Label: expr.String(), Operator: expr.Op}
+		if expr.Op == dsl.OpNot {
+			left := e.expr(entity, permission, expr.Left, depth)
+			right := e.expr(entity, permission, expr.Right, depth)
+			excluded := *right
+			excluded.Excluded = true
+			excluded.Result = map[traceResult]traceResult{traceGranted: traceMissing, traceMissing: traceGranted}[right.Result]
+			if excluded.Result == "" {
+				excluded.Result = traceUnknown
+			}
+			node.Children = []*TraceNode{left, &excluded}
+			node.Result = combine(dsl.OpAnd, []traceResult{left.Result, excluded.Result})
+			return node
+		}
+		results := []traceResult{}
+		for _, operand := range operands(expr) {
+			child := e.expr(entity, permission, operand, depth)
+			node.Children = append(node.Children, child)
+			results = append(results, child.Result)
+		}
+		node.Result = combine(expr.Op, results)
+		return node
+	case *dsl.Ref:
+		if expr.Member == "" {
+			return e.permission(entity, expr.Name, depth)
+		}
+		return e.tupleToUserset(entity, expr, depth)
+	case *dsl.CallExpr:
+		return e.call(entity, permission, expr)
+	}
+	return &TraceNode{Label: expr.String(), Result: traceUnknown}
+}
+
+// operands flattens a chain of the same operator, e.g. a or b or c
+func operands(expr *dsl.BinaryExpr) []dsl.Expr {
+	list := []dsl.Expr{}
+	for _, operand := range []dsl.Expr{expr.Left, expr.Right} {
+		if nested, ok := operand.(*dsl.BinaryExpr); ok && nested.Op == expr.Op {
+			list = append(list, operands(nested)...)
+			continue
+		}
+		list = append(list, operand)
+	}
+	return list
+}
+
+func combine(op string, results []traceResult) traceResult {
+	decisive, other := traceGranted, traceMissing
+	if op == dsl.OpAnd {
+		decisive, other = traceMissing, traceGranted
+	}
+	unknown := false
+	for _, result := range results {
+		if result == decisive {
+			return decisive
+		}
+		unknown = unknown || result == traceUnknown
+	}
+	if unknown {
+		return traceUnknown
+	}
+	return other
+}
+
+// relation explains a relation of an entity, granted by a relationship with the subject
+// or through a subject set like team:1#member that contains the subject
+func (e *explainer) relation(entity *v1.Entity, relation string, depth int) *TraceNode {
+	node := &TraceNode{Label: fmt.Sprintf("%s#%s", utils.EntityToString(entity), relation), Result: traceMissing}
+	tuples, err := e.tuples(entity, relation)
+	if err != nil {
+		node.Result, node.Detail = traceUnknown, err.Error()
+		return node
+	}
+	subject := utils.SubjectToString(e.subject)
+	for _, tuple := range tuples {
+		if utils.SubjectToString(tuple.GetSubject()) == subject {
+			node.Result, node.Detail = traceGranted, e.describe(tuple)
+			return node
+		}
+	}
+	for _, tuple := range tuples {
+		if tuple.GetSubject().GetRelation() == "" {
+			continue
+		}
+		target := &v1.Entity{Type: tuple.GetSubject().GetType(), Id: tuple.GetSubject().GetId()}
+		child := e.permission(target, tuple.GetSubject().GetRelation(), depth+1)
+		node.Children = append(node.Children, child)
+		if child.Result == traceGranted {
+			node.Result, node.Detail = traceGranted, e.describe(tuple)
+			node.Children = []*TraceNode{child}
+			return node
+		}
+		if child.Result == traceUnknown {
+			node.Result = traceUnknown
+		}
+	}
+	node.Detail = fmt.Sprintf("no relationship %s#%s@%s", utils.EntityToString(entity), relation, subject)
+	if len(tuples) > 0 {
+		node.Detail += fmt.Sprintf(", %d relationships with other subjects", len(tuples))
+	}
+	return node
+}
+
+// tupleToUserset explains a reference like parent.admin, granted when a related entity grants the member
+func (e *explainer) tupleToUserset(entity *v1.Entity, ref *dsl.Ref, depth int) *TraceNode {
+	node := &TraceNode{Label: fmt.Sprintf("%s#%s", utils.EntityToString(entity), ref), Result: traceMissing}
+	tuples, err := e.tuples(entity, ref.Name)
+	if err != nil {
+		node.Result, node.Detail = traceUnknown, err.Error()
+		return node
+	}
+	if len(tuples) == 0 {
+		node.Detail = fmt.Sprintf("no relationship %s#%s", utils.EntityToString(entity), ref.Name)
+		return node
+	}
+	for _, tuple := range tuples {
+		target := &v1.Entity{Type: tuple.GetSubject().GetType(), Id: tuple.GetSubject().GetId()}
+		child := e.permission(target, ref.Member, depth+1)
+		node.Children = append(node.Children, child)
+		if child.Result == traceGranted {
+			node.Result, node.Detail = traceGranted, e.describe(tuple)
+			node.Children = []*TraceNode{child}
+			return node
+		}
+		if child.Result == traceUnknown {
+			node.Result = traceUnknown
+		}
+	}
+	return node
+}
+
+// attribute explains a boolean attribute of an entity
+func (e *explainer) attribute(entity *v1.Entity, attribute string) *TraceNode {
+	node := &TraceNode{Label: fmt.Sprintf("%s$%s", utils.EntityToString(entity), attribute), Result: traceMissing}
+	for _, contextAttribute := range e.context.GetAttributes() {
+		if contextAttribute.GetAttribute() == attribute && utils.EntityToString(contextAttribute.GetEntity()) == utils.EntityToString(entity) {
+			return e.attributeValue(node, contextAttribute.GetValue(), "contextual value ")
+		}
+	}
+	readResponse, err := e.dataClient.ReadAttributes(e.ctx, &v1.AttributeReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.AttributeReadRequestMetadata{SnapToken: e.snapToken},
+		Filter: &v1.AttributeFilter{
+			Entity:     &v1.EntityFilter{Type: entity.GetType(), Ids: []string{entity.GetId()}},
+			Attributes: []string{attribute},
+		},
+		PageSize: 1,
+	})
+	if err != nil {
+		node.Result, node.Detail = traceUnknown, err.Error()
+		return node
+	}
+	if len(readResponse.GetAttributes()) == 0 {
+		node.Detail = "attribute is not set"
+		return node
+	}
+	return e.attributeValue(node, readResponse.GetAttributes()[0].GetValue(), "value ")
+}
+
+func (e *explainer) attributeValue(node *TraceNode, attributeValue *anypb.Any, prefix string) *TraceNode {
+	_, value, err := utils.AttributeValueToString(attributeValue)
+	if err != nil {
+		node.Result, node.Detail = traceUnknown, err.Error()
+		return node
+	}
+	node.Detail = prefix + value
+	if value == "true" {
+		node.Result = traceGranted
+	}
+	return node
+}
+
+// call explains a rule call with the argument values of the expansion of the permission.
+// Rules are cel expressions evaluated by permify, so their result is unknown.
+func (e *explainer) call(entity *v1.Entity, permission string, call *dsl.CallExpr) *TraceNode {
+	node := &TraceNode{Label: fmt.Sprintf("%s#%s", utils.EntityToString(entity), call), Result: traceUnknown}
+	if rule := e.schema.Rule(call.Rule); rule != nil {
+		node.Detail = "rule " + rule.Body
+	}
+	expansion, err := e.expand(entity, permission)
+	if err != nil {
+		node.Children = append(node.Children, &TraceNode{Label: "argument values", Result: traceUnknown, Detail: err.Error()})
+		return node
+	}
+	values := findCallValues(expansion, utils.EntityToString(entity), call.Rule)
+	if values == nil {
+		node.Children = append(node.Children, &TraceNode{Label: "argument values", Result: traceUnknown, Detail: "not found in the expansion"})
+		return node
+	}
+	for _, key := range sortedKeys(values.GetValues()) {
+		_, value, err := utils.AttributeValueToString(values.GetValues()[key])
+		if err != nil {
+			value = err.Error()
+		}
+		node.Children = append(node.Children, &TraceNode{Label: key + " = " + value, Result: traceUnknown})
+	}
+	return node
+}
+
+// expand returns the expansion of a permission, expanded once per entity and permission
+func (e *explainer) expand(entity *v1.Entity, permission string) (*v1.Expand, error) {
+	key := utils.EntityToString(entity) + "#" + permission
+	if expansion, ok := e.expansions[key]; ok {
+		return expansion, nil
+	}
+	expandResponse, err := e.permissionClient.Expand(e.ctx, &v1.PermissionExpandRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.PermissionExpandRequestMetadata{
+			SchemaVersion: e.schemaVersion,
+			SnapToken:     e.snapToken,
+		},
+		Entity:     entity,
+		Permission: permission,
+		Context:    e.context,
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.expansions[key] = expandResponse.GetTree()
+	return expandResponse.GetTree(), nil
+}
+
+// findCallValues returns the argument values of the first leaf of the rule on the entity
+func findCallValues(expansion *v1.Expand, entity, rule string) *v1.Values {
+	if expansion == nil {
+		return nil
+	}
+	if values := expansion.GetLeaf().GetValues(); values != nil && expansion.GetPermission() == rule && utils.EntityToString(expansion.GetEntity()) == entity {
+		return values
+	}
+	for _, child := range expansion.GetExpand().GetChildren() {
+		if values := findCallValues(child, entity, rule); values != nil {
+			return values
+		}
+	}
+	return nil
+}
+
+// tuples reads the relationships of a relation of an entity
+func (e *explainer) tuples(entity *v1.Entity, relation string) ([]*v1.Tuple, error) {
+	tuples := []*v1.Tuple{}
+	err := data.ReadAllRelationships(e.ctx, e.dataClient, &v1.RelationshipReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.RelationshipReadRequestMetadata{SnapToken: e.snapToken},
+		Filter: &v1.TupleFilter{
+			Entity:   &v1.EntityFilter{Type: entity.GetType(), Ids: []string{entity.GetId()}},
+			Relation: relation,
+		},
+	}, func(readResponse *v1.RelationshipReadResponse) error {
+		tuples = append(tuples, readResponse.GetTuples()...)
+		return nil
+	})
+	for _, tuple := range e.context.GetTuples() {
+		if tuple.GetRelation() == relation && utils.EntityToString(tuple.GetEntity()) == utils.EntityToString(entity) {
+			tuples = append(tuples, tuple)
+		}
+	}
+	return tuples, err
+}
+
+// describe names the relationship of a tuple, telling contextual tuples apart from written ones
+func (e *explainer) describe(tuple *v1.Tuple) string {
+	for _, contextTuple := range e.context.GetTuples() {
+		if contextTuple == tuple {
+			return "contextual relationship " + utils.TupleToString(tuple)
+		}
+	}
+	return "relationship " + utils.TupleToString(tuple)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printExplanation prints the trace, the path that granted the permission or the missing relations,
+// and whether the trace agrees with the check
+func printExplanation(explanation *Explanation) {
+	paint := func(style func(string) string, s string) string {
+		if printer.Color {
+			return style(s)
+		}
+		return s
+	}
+	markers := map[traceResult]string{
+		traceGranted: paint(tui.Blue, "✓"),
+		traceMissing: paint(tui.Critical, "✗"),
+		traceUnknown: paint(tui.Pink, "?"),
+	}
+	fmt.Fprint(printer.Out, drawTree(explanation.Trace, func(n *TraceNode) []*TraceNode { return n.Children }, func(n *TraceNode) string {
+		label := n.Label
+		if n.Expression != "" {
+			label += " = " + n.Expression
+		}
+		if n.Excluded {
+			label = "not " + label
+		}
+		label = markers[n.Result] + " " + label
+		if n.Detail != "" {
+			label += "  " + n.Detail
+		}
+		return label
+	}))
+	fmt.Println()
+
+	if explanation.Result == ResultAllowed {
+		fmt.Println(paint(tui.Blue, "allowed"))
+	} else {
+		fmt.Println(paint(tui.Critical, "denied"))
+	}
+	switch explanation.Trace.Result {
+	case traceGranted:
+		fmt.Println("granted through " + grantedPath(explanation.Trace))
+	case traceMissing:
+		fmt.Println("because of:")
+		var walk func(node *TraceNode)
+		walk = func(node *TraceNode) {
+			switch {
+			case node.Result != traceMissing:
+			case node.Excluded:
+				fmt.Println("  excluded by " + grantedPath(node))
+			case len(node.Children) == 0:
+				fmt.Println("  " + node.Detail)
+			default:
+				for _, child := range node.Children {
+					walk(child)
+				}
+			}
+		}
+		walk(explanation.Trace)
+	default:
+		fmt.Println("the result depends on rules, which are evaluated by permify only. Their argument values are listed above")
+	}
+
+	traceAllowed := explanation.Trace.Result == traceGranted
+	if explanation.Trace.Result != traceUnknown && traceAllowed != (explanation.Result == ResultAllowed) {
+		log.Warn("the trace does not match the check result, the data may have changed since the check")
+	}
+}
+
+// grantedPath follows the granted children of node down to the relationship that grants it
+func grantedPath(node *TraceNode) string {
+	path := []string{}
+	for node != nil {
+		if node.Operator == "" {
+			path = append(path, node.Label)
+		}
+		var next *TraceNode
+		for _, child := range node.Children {
+			if child.Result == traceGranted && !child.Excluded {
+				next = child
+				break
+			}
+		}
+		if next == nil && node.Detail != "" {
+			path = append(path, node.Detail)
+		}
+		node = next
+	}
+	return strings.Join(path, " → ")
+}
@@ -1,13 +1,20 @@
 package permission
 
 import (
+	"container/heap"
 	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
 	"os"
+	"sort"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
+	"github.com/Permify/permify-cli/core/cmd/data"
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/tui"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
@@ -51,6 +58,18 @@ func (le *LookupEntityCmd) Cmd() *cobra.Command {
 	cmd.Flags().StringP("type", "t", "", "entity type to lookup")
 	cmd.Flags().StringP("permission", "p", "", "permission to check")
 	cmd.Flags().StringP("subject", "s", "", "subject identifier specified as - <type>:<id>#relation (relation is optional)")
+	cmd.Flags().Bool("stream", false, "print entity ids as they arrive, as plain lines or with -o ndjson as json lines")
+	cmd.Flags().Int32("page-size", 0, "number of entity ids per page, prints the token of the next page. Every page streams the whole lookup")
+	cmd.Flags().String("continuous-token", "", "token of the page to print, from the previous page")
+	cmd.Flags().Bool("count", false, "print the number of entities only")
+	cmd.MarkFlagsMutuallyExclusive("stream", "page-size")
+	cmd.MarkFlagsMutuallyExclusive("stream", "continuous-token")
+	cmd.MarkFlagsMutuallyExclusive("stream", "count")
+	cmd.MarkFlagsMutuallyExclusive("count", "page-size")
+	cmd.MarkFlagsMutuallyExclusive("count", "continuous-token")
+	addContextFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
+	cmd.SetHelpFunc(utils.CmdHelp)
 	return cmd
 }
 
@@ -68,6 +87,10 @@ func (le *LookupEntityCmd) Run(cmd *cobra.Command, args []string) {
 		subject = newSubject 
 	}
 	parsedSubject, err := utils.ParseSubject(subject)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
 
 	permission, _ := cmd.Flags().GetString("permission")
 	if permission == "" {
@@ -97,23 +120,169 @@ func (le *LookupEntityCmd) Run(cmd *cobra.Command, args []string) {
 		entityType = newEntityType
 	}
 
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
 	permissionClient := Client()
 	lookupRequest := &v1.PermissionLookupEntityRequest{
 		TenantId: config.CliConfig.Tenant,	
 		Metadata: &v1.PermissionLookupEntityRequestMetadata{
 			SchemaVersion: schemaVersion,
+			SnapToken: snapToken,
 			Depth: depth,
 		},
 		Subject: parsedSubject,
 		EntityType: entityType,
 		Permission: permission,
+		Context: requestContext,
+	}
+
+	stream, _ := cmd.Flags().GetBool("stream")
+	count, _ := cmd.Flags().GetBool("count")
+	pageSize, _ := cmd.Flags().GetInt32("page-size")
+	continuousToken, _ := cmd.Flags().GetString("continuous-token")
+	switch {
+	case stream:
+		format := printer.Current()
+		if cmd.Flags().Changed("output") && format != printer.NDJSON && format != printer.Raw {
+			log.Error("--stream prints plain lines, or json lines with -o ndjson or -o raw")
+			os.Exit(1)
+		}
+		err = streamLookupEntity(context.Background(), permissionClient, lookupRequest, func(id string) error {
+			if !cmd.Flags().Changed("output") {
+				_, err := fmt.Fprintln(printer.Out, id)
+				return err
+			}
+			printer.Print(&v1.PermissionLookupEntityStreamResponse{EntityId: id})
+			return nil
+		})
+	case count:
+		n := 0
+		err = streamLookupEntity(context.Background(), permissionClient, lookupRequest, func(id string) error {
+			n++
+			return nil
+		})
+		if err == nil {
+			printer.Print(&LookupEntityCount{EntityType: entityType, Permission: permission, Subject: utils.SubjectToString(parsedSubject), Count: n})
+		}
+	case pageSize > 0 || continuousToken != "":
+		var page *LookupEntityPage
+		page, err = lookupEntityPage(context.Background(), permissionClient, lookupRequest, pageSize, continuousToken)
+		if err == nil {
+			printer.Print(page)
+		}
+	default:
+		var lookupResponse *v1.PermissionLookupEntityResponse
+		lookupResponse, err = permissionClient.LookupEntity(context.Background(), lookupRequest)
+		if err == nil {
+			printer.Print(lookupResponse)
+		}
 	}
-	lookupResponse, err := permissionClient.LookupEntity(context.Background(), lookupRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(lookupResponse)	
+}
+
+// LookupEntityCount is printed by lookup entity --count
+type LookupEntityCount struct {
+	EntityType string `json:"entity_type"`
+	Permission string `json:"permission"`
+	Subject    string `json:"subject"`
+	Count      int    `json:"count"`
+}
+
+// LookupEntityPage is a page of the entity ids of a lookup, ContinuousToken is empty on the last page
+type LookupEntityPage struct {
+	EntityIds       []string `json:"entity_ids"`
+	ContinuousToken string   `json:"continuous_token,omitempty"`
+}
+
+// streamLookupEntity calls fn for every entity id of the lookup as it arrives
+func streamLookupEntity(ctx context.Context, permissionClient v1.PermissionClient, request *v1.PermissionLookupEntityRequest, fn func(id string) error) error {
+	stream, err := permissionClient.LookupEntityStream(ctx, request)
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(response.GetEntityId()); err != nil {
+			return err
+		}
+	}
+}
+
+// lookupEntityPage returns the page of entity ids after the token. The lookup api has no pagination,
+// so the ids are streamed and paged in sorted order, the token holds the last id of the previous page.
+// Only the pageSize+1 smallest ids after the token are kept while streaming, the extra one tells
+// whether there is a next page.
+func lookupEntityPage(ctx context.Context, permissionClient v1.PermissionClient, request *v1.PermissionLookupEntityRequest, pageSize int32, continuousToken string) (*LookupEntityPage, error) {
+	if pageSize <= 0 {
+		pageSize = data.DefaultPageSize
+	}
+	after := ""
+	if continuousToken != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(continuousToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid continuous token: %w", err)
+		}
+		after = string(decoded)
+	}
+	smallest := &idHeap{}
+	kept := map[string]bool{}
+	err := streamLookupEntity(ctx, permissionClient, request, func(id string) error {
+		switch {
+		case id <= after || kept[id]:
+		case smallest.Len() <= int(pageSize):
+			heap.Push(smallest, id)
+			kept[id] = true
+		case id < (*smallest)[0]:
+			delete(kept, (*smallest)[0])
+			(*smallest)[0] = id
+			kept[id] = true
+			heap.Fix(smallest, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := []string(*smallest)
+	sort.Strings(ids)
+	page := &LookupEntityPage{EntityIds: ids}
+	if len(ids) > int(pageSize) {
+		page.EntityIds = ids[:pageSize]
+		page.ContinuousToken = base64.RawURLEncoding.EncodeToString([]byte(ids[pageSize-1]))
+	}
+	return page, nil
+}
+
+// idHeap is a max-heap of entity ids
+type idHeap []string
+
+func (h idHeap) Len() int           { return len(h) }
+func (h idHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h idHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x any)        { *h = append(*h, x.(string)) }
+func (h *idHeap) Pop() any {
+	old := *h
+	id := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return id
 }
 
 type LookupSubjectCmd struct {
@@ -132,6 +301,8 @@ func (ls *LookupSubjectCmd) Cmd() *cobra.Command {
 	cmd.Flags().StringP("type", "t", "", "subject type to lookup")
 	cmd.Flags().StringP("relation", "r", "", "[Optional] subject relation to lookup")
 	cmd.Flags().StringP("entity", "e", "", "entity identifier specified as - <type>:<id>")
+	addContextFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
 	return cmd
 }
 
@@ -184,11 +355,23 @@ func (ls *LookupSubjectCmd) Run(cmd *cobra.Command, args []string) {
 
 	subjectRelation, _ := cmd.Flags().GetString("relation")
 
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
 	permissionClient := Client()
 	lookupRequest := &v1.PermissionLookupSubjectRequest{
 		TenantId: config.CliConfig.Tenant,
 		Metadata: &v1.PermissionLookupSubjectRequestMetadata{
 			SchemaVersion: schemaVersion,
+			SnapToken: snapToken,
 			Depth: depth,
 		},
 		Entity: parsedEntity,
@@ -197,11 +380,12 @@ func (ls *LookupSubjectCmd) Run(cmd *cobra.Command, args []string) {
 			Type: subjectType,
 			Relation: subjectRelation,
 		},
+		Context: requestContext,
 	}
 	lookupResponse, err := permissionClient.LookupSubject(context.Background(), lookupRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(lookupResponse)
+	printer.Print(lookupResponse)
 }
\ No newline at end of file
@@ -19,11 +19,13 @@ func New() *cobra.Command {
 	}
 	checkCmd := CheckCmd{"check"}
 	expandCmd := ExpandCmd{"expand"}
+	explainCmd := ExplainCmd{"explain"}
 	lookupCmd := LookupCmd{"lookup"}
 	subjectcmd := SubjectCmd{"subject"}
 
 	permissionCmd.AddCommand(checkCmd.Cmd())
 	permissionCmd.AddCommand(expandCmd.Cmd())
+	permissionCmd.AddCommand(explainCmd.Cmd())
 	permissionCmd.AddCommand(lookupCmd.Cmd())
 	permissionCmd.AddCommand(subjectcmd.Cmd())
 
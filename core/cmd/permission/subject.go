@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/tui"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
@@ -31,6 +32,8 @@ func (sc *SubjectCmd) Cmd() *cobra.Command {
 	cmd.Flags().StringP("subject", "s", "", "subject identifier specified as - <type>:<id>#relation (relation is optional)")
 	cmd.Flags().Int32("depth", 50, "depth of the check must be >= 3")
 	cmd.Flags().BoolP("only-permission", "p", false, "return only permissions. Default: false")
+	addContextFlags(cmd)
+	utils.AddSnapTokenFlags(cmd)
 	return cmd
 }
 
@@ -64,22 +67,39 @@ func (sc *SubjectCmd) Run(cmd *cobra.Command, args []string) {
 		subject = newSubject 
 	}
 	parsedSubject, err := utils.ParseSubject(subject)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	requestContext, err := contextFromFlags(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	snapToken, err := utils.SnapToken(cmd)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
 
 	permissionClient := Client()
 	subjectRequest := &v1.PermissionSubjectPermissionRequest{
 		TenantId: config.CliConfig.Tenant,
 		Metadata: &v1.PermissionSubjectPermissionRequestMetadata{
 			SchemaVersion: schemaVersion,
+			SnapToken: snapToken,
 			OnlyPermission: onlyPermission,
 			Depth: depth,
 		},	
 		Entity: parsedEntity,
 		Subject: parsedSubject,
+		Context: requestContext,
 	}
 	subjectResponse, err := permissionClient.SubjectPermission(context.Background(), subjectRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(subjectResponse)
+	printer.Print(subjectResponse)
 }
@@ -0,0 +1,261 @@
+package permission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// expandNodeKind is what a node of an expand tree stands for
+type expandNodeKind int
+
+const (
+	// expandOperation combines its children with union, intersection or exclusion
+	expandOperation expandNodeKind = iota
+	// expandLeaf is a relation or attribute whose subjects or values are its children
+	expandLeaf
+	expandSubject
+	expandValue
+	// expandNote stands for collapsed or missing children
+	expandNote
+)
+
+// expandNode is a node of an expand tree prepared for rendering
+type expandNode struct {
+	Kind      expandNodeKind
+	Label     string
+	Operation string
+	// Via is set when the node belongs to another entity than its parent, i.e. it was reached through a relation
+	Via bool
+	// Excluded is set for the operands an exclusion removes from its first operand
+	Excluded bool
+	Children []*expandNode
+}
+
+// buildExpandTree converts an expand response into nodes, subject lists longer than collapse
+// are cut to collapse subjects and a note. Nothing is collapsed when collapse is 0.
+func buildExpandTree(e *v1.Expand, parent *v1.Entity, collapse int) *expandNode {
+	node := &expandNode{
+		Label: fmt.Sprintf("%s#%s", utils.EntityToString(e.GetEntity()), e.GetPermission()),
+		Via:   parent != nil && utils.EntityToString(parent) != utils.EntityToString(e.GetEntity()),
+	}
+	if arguments := expandArguments(e.GetArguments()); arguments != "" {
+		node.Label += "(" + arguments + ")"
+	}
+
+	if tree := e.GetExpand(); tree != nil {
+		node.Kind = expandOperation
+		node.Operation = strings.ToLower(strings.TrimPrefix(tree.GetOperation().String(), "OPERATION_"))
+		for i, child := range tree.GetChildren() {
+			childNode := buildExpandTree(child, e.GetEntity(), collapse)
+			childNode.Excluded = tree.GetOperation() == v1.ExpandTreeNode_OPERATION_EXCLUSION && i > 0
+			node.Children = append(node.Children, childNode)
+		}
+		return node
+	}
+
+	node.Kind = expandLeaf
+	leaf := e.GetLeaf()
+	switch {
+	case leaf.GetSubjects() != nil:
+		subjects := leaf.GetSubjects().GetSubjects()
+		shown := subjects
+		if collapse > 0 && len(subjects) > collapse {
+			shown = subjects[:collapse]
+		}
+		for _, subject := range shown {
+			node.Children = append(node.Children, &expandNode{Kind: expandSubject, Label: utils.SubjectToString(subject)})
+		}
+		if len(shown) < len(subjects) {
+			node.Children = append(node.Children, &expandNode{Kind: expandNote, Label: fmt.Sprintf("%d more subjects", len(subjects)-len(shown))})
+		}
+		if len(subjects) == 0 {
+			node.Children = append(node.Children, &expandNode{Kind: expandNote, Label: "no subjects"})
+		}
+	case leaf.GetValues() != nil:
+		values := leaf.GetValues().GetValues()
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			_, value, err := utils.AttributeValueToString(values[key])
+			if err != nil {
+				value = err.Error()
+			}
+			node.Children = append(node.Children, &expandNode{Kind: expandValue, Label: key + " = " + value})
+		}
+	case leaf.GetValue() != nil:
+		_, value, err := utils.AttributeValueToString(leaf.GetValue())
+		if err != nil {
+			value = err.Error()
+		}
+		node.Label += " = " + value
+	}
+	return node
+}
+
+func expandArguments(arguments []*v1.Argument) string {
+	names := []string{}
+	for _, argument := range arguments {
+		if argument.GetContextAttribute() != nil {
+			names = append(names, "request."+argument.GetContextAttribute().GetName())
+			continue
+		}
+		names = append(names, argument.GetComputedAttribute().GetName())
+	}
+	return strings.Join(names, ", ")
+}
+
+// text returns the label with the operation, relation and exclusion markers, colored when color is set
+func (n *expandNode) text(color bool) string {
+	paint := func(style func(string) string, s string) string {
+		if color {
+			return style(s)
+		}
+		return s
+	}
+	label := n.Label
+	switch n.Kind {
+	case expandOperation:
+		operationStyle := tui.Blue
+		switch n.Operation {
+		case "intersection":
+			operationStyle = tui.Pink
+		case "exclusion":
+			operationStyle = tui.Warning
+		}
+		label += " " + paint(operationStyle, n.Operation)
+	case expandLeaf:
+		label = paint(tui.Blue, label)
+	case expandNote:
+		label = "… " + label
+	}
+	if n.Via {
+		label = "→ " + label
+	}
+	if n.Excluded {
+		label = paint(tui.Warning, "not ") + label
+	}
+	return label
+}
+
+// renderExpandTree draws the tree with box drawing characters
+func renderExpandTree(root *expandNode, color bool) string {
+	return drawTree(root, func(n *expandNode) []*expandNode { return n.Children }, func(n *expandNode) string { return n.text(color) })
+}
+
+// drawTree draws a tree with box drawing characters, a line per node
+func drawTree[T any](root T, children func(T) []T, label func(T) string) string {
+	s := strings.Builder{}
+	s.WriteString(label(root) + "\n")
+	var walk func(node T, prefix string)
+	walk = func(node T, prefix string) {
+		nodes := children(node)
+		for i, child := range nodes {
+			branch, indent := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, indent = "└── ", "    "
+			}
+			s.WriteString(prefix + branch + label(child) + "\n")
+			walk(child, prefix+indent)
+		}
+	}
+	walk(root, "")
+	return s.String()
+}
+
+// walkExpandTree calls fn for every node with the id of the node and of its parent, the root has parent -1
+func walkExpandTree(root *expandNode, fn func(id, parent int, node *expandNode)) {
+	next := 0
+	var walk func(node *expandNode, parent int)
+	walk = func(node *expandNode, parent int) {
+		id := next
+		next++
+		fn(id, parent, node)
+		for _, child := range node.Children {
+			walk(child, id)
+		}
+	}
+	walk(root, -1)
+}
+
+var expandDOTShapes = map[expandNodeKind]string{
+	expandOperation: "ellipse",
+	expandLeaf:      "box",
+	expandSubject:   "box, style=rounded",
+	expandValue:     "box, style=rounded",
+	expandNote:      "plaintext",
+}
+
+// renderExpandDOT renders the tree in the graphviz dot language
+func renderExpandDOT(root *expandNode) string {
+	s := strings.Builder{}
+	s.WriteString("digraph expand {\n")
+	s.WriteString("    node [fontname=\"Helvetica\"];\n")
+	s.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+	walkExpandTree(root, func(id, parent int, node *expandNode) {
+		label := node.Label
+		if node.Kind == expandOperation {
+			label += "\n" + node.Operation
+		}
+		fmt.Fprintf(&s, "    n%d [label=%q, shape=%s];\n", id, label, expandDOTShapes[node.Kind])
+		if parent < 0 {
+			return
+		}
+		attributes := []string{}
+		if node.Excluded {
+			attributes = append(attributes, "label=\"not\"")
+		}
+		if node.Via {
+			attributes = append(attributes, "style=dashed")
+		}
+		if len(attributes) == 0 {
+			fmt.Fprintf(&s, "    n%d -> n%d;\n", parent, id)
+		} else {
+			fmt.Fprintf(&s, "    n%d -> n%d [%s];\n", parent, id, strings.Join(attributes, ", "))
+		}
+	})
+	s.WriteString("}\n")
+	return s.String()
+}
+
+var expandMermaidShapes = map[expandNodeKind][2]string{
+	expandOperation: {"([", "])"},
+	expandLeaf:      {"[", "]"},
+	expandSubject:   {"(", ")"},
+	expandValue:     {"(", ")"},
+	expandNote:      {">", "]"},
+}
+
+// renderExpandMermaid renders the tree as a mermaid flowchart
+func renderExpandMermaid(root *expandNode) string {
+	s := strings.Builder{}
+	s.WriteString("flowchart TD\n")
+	walkExpandTree(root, func(id, parent int, node *expandNode) {
+		label := strings.ReplaceAll(node.Label, "\"", "#quot;")
+		if node.Kind == expandOperation {
+			label += "<br/>" + node.Operation
+		}
+		shape := expandMermaidShapes[node.Kind]
+		fmt.Fprintf(&s, "    n%d%s\"%s\"%s\n", id, shape[0], label, shape[1])
+		if parent < 0 {
+			return
+		}
+		arrow := "-->"
+		if node.Via {
+			arrow = "-.->"
+		}
+		if node.Excluded {
+			fmt.Fprintf(&s, "    n%d %s|not| n%d\n", parent, arrow, id)
+		} else {
+			fmt.Fprintf(&s, "    n%d %s n%d\n", parent, arrow, id)
+		}
+	})
+	return s.String()
+}
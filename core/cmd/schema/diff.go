@@ -0,0 +1,256 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/cmd/data"
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// Exit codes of the diff command
+const (
+	ExitCompatible = 0
+	ExitBreaking   = 1
+	ExitError      = 2
+)
+
+// DiffCmd - compares a schema file or a schema version with another schema version
+type DiffCmd struct {
+	Command string
+}
+
+// Cmd - diff command
+func (dc *DiffCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   dc.Command,
+		Short: "show the changes between two schemas",
+		Run:   dc.Run,
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			config.ExitCodeAnnotation: strconv.Itoa(ExitError),
+		},
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addSchemaFileFlag(cmd, "perm schema files or glob patterns compared with the schema version of --schema, or the head. Use - to read from stdin")
+	cmd.Flags().String("from", "", "schema version to compare from instead of the --schema version")
+	cmd.Flags().String("to", "", "schema version to compare to when no file is given. Defaults to the head")
+	cmd.MarkFlagsMutuallyExclusive("file", "to")
+	cmd.MarkFlagsOneRequired("file", "from")
+	return cmd
+}
+
+func (dc *DiffCmd) Run(cmd *cobra.Command, args []string) {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	fromVersion, _ := cmd.Flags().GetString("from")
+	toVersion, _ := cmd.Flags().GetString("to")
+	if fromVersion == "" {
+		fromVersion, _ = cmd.Flags().GetString("schema")
+	}
+
+	ctx := context.Background()
+	from, warnings, err := readSchema(ctx, fromVersion)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(ExitError)
+	}
+	logWarnings(warnings)
+
+	var to *dsl.Schema
+	if len(files) > 0 {
+		schema, diagnostics := parseSchemaFiles(files)
+		if schema == nil {
+			printDiagnostics(diagnostics)
+			os.Exit(ExitError)
+		}
+		if dsl.HasErrors(dsl.Validate(schema)) {
+			log.Warn("the schema files have errors, run `permctl schema validate` for details")
+		}
+		to = schema
+	} else {
+		var warnings []dsl.Diagnostic
+		to, warnings, err = readSchema(ctx, toVersion)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(ExitError)
+		}
+		logWarnings(warnings)
+	}
+
+	changes := dsl.Diff(from, to)
+	if len(changes) == 0 {
+		log.Info("no changes")
+		return
+	}
+	breaking, err := markBreaking(ctx, changes)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(ExitError)
+	}
+	for _, change := range changes {
+		switch {
+		case change.Breaking:
+			fmt.Println(tui.Critical(change.String()))
+		case change.Kind == dsl.Added:
+			fmt.Println(tui.Blue(change.String()))
+		case change.Kind == dsl.Removed:
+			fmt.Println(tui.Warning(change.String()))
+		default:
+			fmt.Println(change.String())
+		}
+	}
+	if breaking > 0 {
+		log.Error("the schema change removes definitions that still have data", "breaking", breaking)
+		os.Exit(ExitBreaking)
+	}
+}
+
+// readSchema reads a schema version of the tenant, the head when version is empty.
+// The warnings are those of dsl.FromDefinition.
+func readSchema(ctx context.Context, version string) (*dsl.Schema, []dsl.Diagnostic, error) {
+	readResponse, err := Client().Read(ctx, &v1.SchemaReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.SchemaReadRequestMetadata{
+			SchemaVersion: version,
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dsl.FromDefinition(readResponse.Schema)
+}
+
+// logWarnings logs the warnings of a schema read from permify
+func logWarnings(warnings []dsl.Diagnostic) {
+	for _, warning := range warnings {
+		log.Warn(warning.Message, "code", warning.Code)
+	}
+}
+
+// markBreaking flags removed entities, relations, relation types and attributes that still have data
+func markBreaking(ctx context.Context, changes []dsl.Change) (int, error) {
+	dataClient := data.Client()
+	breaking := 0
+	for i, change := range changes {
+		reason := ""
+		switch {
+		case change.Kind == dsl.Removed && change.Object == "entity":
+			found, err := hasRelationships(ctx, dataClient, &v1.TupleFilter{Entity: &v1.EntityFilter{Type: change.Entity}})
+			if err != nil {
+				return 0, err
+			}
+			if found {
+				reason = "relationships exist"
+			}
+		case change.Kind == dsl.Removed && change.Object == "relation":
+			found, err := hasRelationships(ctx, dataClient, &v1.TupleFilter{
+				Entity:   &v1.EntityFilter{Type: change.Entity},
+				Relation: change.Name,
+			})
+			if err != nil {
+				return 0, err
+			}
+			if found {
+				reason = "relationships exist"
+			}
+		case change.Kind == dsl.Changed && change.Object == "relation":
+			for _, relationType := range change.RemovedTypes {
+				found, err := hasRelationshipsWithSubject(ctx, dataClient, &v1.TupleFilter{
+					Entity:   &v1.EntityFilter{Type: change.Entity},
+					Relation: change.Name,
+					Subject:  &v1.SubjectFilter{Type: relationType.Type, Relation: relationType.Relation},
+				})
+				if err != nil {
+					return 0, err
+				}
+				if found {
+					reason = fmt.Sprintf("relationships with %s exist", relationType)
+					break
+				}
+			}
+		case change.Object == "attribute" && change.Kind != dsl.Added:
+			found, err := hasAttributes(ctx, dataClient, &v1.AttributeFilter{
+				Entity:     &v1.EntityFilter{Type: change.Entity},
+				Attributes: []string{change.Name},
+			})
+			if err != nil {
+				return 0, err
+			}
+			if found {
+				reason = "attribute values exist"
+			}
+		}
+		if reason != "" {
+			changes[i].Breaking = true
+			changes[i].Reason = reason
+			breaking++
+		}
+	}
+	return breaking, nil
+}
+
+// hasRelationships reports whether at least one relationship matches the filter
+func hasRelationships(ctx context.Context, dataClient v1.DataClient, filter *v1.TupleFilter) (bool, error) {
+	readResponse, err := dataClient.ReadRelationships(ctx, &v1.RelationshipReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.RelationshipReadRequestMetadata{},
+		Filter:   filter,
+		PageSize: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(readResponse.Tuples) > 0, nil
+}
+
+// errFound stops reading relationships once a match is found
+var errFound = errors.New("found")
+
+// hasRelationshipsWithSubject reports whether at least one relationship matches the filter and the exact
+// subject relation of it. A filter without a subject relation matches every subject relation, so the
+// relationships of the subject type are read until one without a subject relation is found.
+func hasRelationshipsWithSubject(ctx context.Context, dataClient v1.DataClient, filter *v1.TupleFilter) (bool, error) {
+	if filter.Subject.Relation != "" {
+		return hasRelationships(ctx, dataClient, filter)
+	}
+	err := data.ReadAllRelationships(ctx, dataClient, &v1.RelationshipReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.RelationshipReadRequestMetadata{},
+		Filter:   filter,
+	}, func(response *v1.RelationshipReadResponse) error {
+		for _, tuple := range response.Tuples {
+			if tuple.GetSubject().GetRelation() == "" {
+				return errFound
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// hasAttributes reports whether at least one attribute matches the filter
+func hasAttributes(ctx context.Context, dataClient v1.DataClient, filter *v1.AttributeFilter) (bool, error) {
+	readResponse, err := dataClient.ReadAttributes(ctx, &v1.AttributeReadRequest{
+		TenantId: config.CliConfig.Tenant,
+		Metadata: &v1.AttributeReadRequestMetadata{},
+		Filter:   filter,
+		PageSize: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(readResponse.Attributes) > 0, nil
+}
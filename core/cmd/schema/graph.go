@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/utils"
+)
+
+// GraphCmd - renders a schema as a graph
+type GraphCmd struct {
+	Command string
+}
+
+// Cmd - graph command
+func (gc *GraphCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   gc.Command,
+		Short: "render the schema as a dot or mermaid graph",
+		Run:   gc.Run,
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			config.OfflineAnnotation: "true",
+		},
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addSchemaFileFlag(cmd, "perm schema files or glob patterns. The schema of the tenant is read when not set")
+	cmd.Flags().String("format", "dot", "graph format (dot|mermaid)")
+	cmd.Flags().String("focus", "", "only show an entity specified as - <entity> or what a member reaches specified as - <entity>#<member>")
+	return cmd
+}
+
+func (gc *GraphCmd) Run(cmd *cobra.Command, args []string) {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	format, _ := cmd.Flags().GetString("format")
+	focus, _ := cmd.Flags().GetString("focus")
+	if format != "dot" && format != "mermaid" {
+		log.Error("unknown graph format, expected dot or mermaid", "format", format)
+		os.Exit(1)
+	}
+
+	var schema *dsl.Schema
+	if len(files) > 0 {
+		parsed, diagnostics := parseSchemaFiles(files)
+		if parsed == nil {
+			printDiagnostics(diagnostics)
+			os.Exit(1)
+		}
+		schema = parsed
+	} else {
+		// the command runs offline with --file, reading the tenant schema needs a configured permify
+		if config.CliConfig.PermifyURL == "" {
+			log.Error("permctl is not configured. Please run `permctl configure` or set --file")
+			os.Exit(1)
+		}
+		schemaVersion, _ := cmd.Flags().GetString("schema")
+		// the graph does not show rule parameters, their order does not matter here
+		read, _, err := readSchema(context.Background(), schemaVersion)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		schema = read
+	}
+
+	graph := dsl.BuildGraph(schema)
+	if focus != "" {
+		entity, member, _ := strings.Cut(focus, "#")
+		focused, err := graph.Focus(entity, member)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		graph = focused
+	}
+
+	if format == "mermaid" {
+		fmt.Fprint(printer.Out, graph.Mermaid())
+		return
+	}
+	fmt.Fprint(printer.Out, graph.DOT())
+}
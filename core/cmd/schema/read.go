@@ -2,12 +2,15 @@ package schema
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
 )
@@ -43,5 +46,17 @@ func (rc *ReadCmd) Run(cmd *cobra.Command, args []string) {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(readResponse)
+
+	// the perm source is printed unless an output format is asked for explicitly
+	if cmd.Flags().Changed("output") {
+		printer.Print(readResponse)
+		return
+	}
+	schema, warnings, err := dsl.FromDefinition(readResponse.Schema)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	logWarnings(warnings)
+	fmt.Fprint(printer.Out, dsl.Format(schema))
 }
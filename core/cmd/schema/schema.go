@@ -19,9 +19,17 @@ func New() *cobra.Command {
 	}
 	readCmd := ReadCmd{"read"}
 	writeCmd := WriteCmd{"write"}
+	validateCmd := ValidateCmd{"validate"}
+	lintCmd := LintCmd{"lint"}
+	diffCmd := DiffCmd{"diff"}
+	graphCmd := GraphCmd{"graph"}
 
 	schemaCmd.AddCommand(readCmd.Cmd())
 	schemaCmd.AddCommand(writeCmd.Cmd())
+	schemaCmd.AddCommand(validateCmd.Cmd())
+	schemaCmd.AddCommand(lintCmd.Cmd())
+	schemaCmd.AddCommand(diffCmd.Cmd())
+	schemaCmd.AddCommand(graphCmd.Cmd())
 
 	return schemaCmd
 }
@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+)
+
+// ValidateCmd - checks a schema file for errors without a server connection
+type ValidateCmd struct {
+	Command string
+}
+
+// Cmd - validate command
+func (vc *ValidateCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   vc.Command,
+		Short: "check a schema file for errors",
+		Run:   vc.Run,
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			config.OfflineAnnotation: "true",
+		},
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addSchemaFileFlag(cmd, "perm schema files or glob patterns. Use - to read from stdin")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (vc *ValidateCmd) Run(cmd *cobra.Command, args []string) {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	schema, diagnostics := parseSchemaFiles(files)
+	if schema != nil {
+		diagnostics = dsl.Validate(schema)
+	}
+	printDiagnostics(diagnostics)
+	if dsl.HasErrors(diagnostics) {
+		os.Exit(1)
+	}
+	log.Info("schema is valid")
+}
+
+// LintCmd - checks a schema file for errors and smells without a server connection
+type LintCmd struct {
+	Command string
+}
+
+// Cmd - lint command
+func (lc *LintCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   lc.Command,
+		Short: "check a schema file for errors and smells",
+		Run:   lc.Run,
+		Args:  cobra.NoArgs,
+		Annotations: map[string]string{
+			config.OfflineAnnotation: "true",
+		},
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	addSchemaFileFlag(cmd, "perm schema files or glob patterns. Use - to read from stdin")
+	cmd.Flags().Bool("strict", false, "exit with an error on warnings as well")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (lc *LintCmd) Run(cmd *cobra.Command, args []string) {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	strict, _ := cmd.Flags().GetBool("strict")
+	schema, diagnostics := parseSchemaFiles(files)
+	if schema != nil {
+		diagnostics = dsl.Lint(schema)
+	}
+	printDiagnostics(diagnostics)
+	if dsl.HasErrors(diagnostics) || (strict && len(diagnostics) > 0) {
+		os.Exit(1)
+	}
+	if len(diagnostics) == 0 {
+		log.Info("no problems found")
+	}
+}
+
+// addSchemaFileFlag registers the repeatable --file flag of commands reading perm files
+func addSchemaFileFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringSliceP("file", "f", nil, usage)
+}
+
+// parseSchemaFiles parses perm files into a single schema, syntax errors are returned as diagnostics
+func parseSchemaFiles(patterns []string) (*dsl.Schema, []dsl.Diagnostic) {
+	schema, diagnostics, err := loadSchemaFiles(patterns)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	return schema, diagnostics
+}
+
+// loadSchemaFiles is parseSchemaFiles returning file errors instead of exiting
+func loadSchemaFiles(patterns []string) (*dsl.Schema, []dsl.Diagnostic, error) {
+	sources, err := readSchemaSources(patterns)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseSchemaSources(sources)
+}
+
+// schemaSource is the content of a perm file
+type schemaSource struct {
+	file   string
+	source string
+}
+
+// readSchemaSources reads the perm files of the patterns in sorted order
+func readSchemaSources(patterns []string) ([]schemaSource, error) {
+	files, err := utils.SchemaFiles(patterns)
+	if err != nil {
+		return nil, err
+	}
+	sources := []schemaSource{}
+	for _, file := range files {
+		source, err := utils.ReadFileToString(file)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, schemaSource{file: file, source: source})
+	}
+	return sources, nil
+}
+
+// parseSchemaSources parses the perm files into a single schema, syntax errors are returned as diagnostics.
+// Every file is parsed on its own so that positions point into the right file.
+func parseSchemaSources(sources []schemaSource) (*dsl.Schema, []dsl.Diagnostic, error) {
+	schema := &dsl.Schema{}
+	diagnostics := []dsl.Diagnostic{}
+	for _, source := range sources {
+		parsed, err := dsl.Parse(source.file, source.source)
+		var syntaxErr *dsl.Error
+		if errors.As(err, &syntaxErr) {
+			diagnostics = append(diagnostics, dsl.Diagnostic{
+				Pos:      syntaxErr.Pos,
+				Severity: dsl.SeverityError,
+				Code:     "syntax",
+				Message:  syntaxErr.Message,
+			})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		schema.Entities = append(schema.Entities, parsed.Entities...)
+		schema.Rules = append(schema.Rules, parsed.Rules...)
+	}
+	if len(diagnostics) > 0 {
+		return nil, diagnostics, nil
+	}
+	return schema, nil, nil
+}
+
+// joinSchemaSources joins the contents of the perm files in their order
+func joinSchemaSources(sources []schemaSource) string {
+	contents := []string{}
+	for _, source := range sources {
+		contents = append(contents, source.source)
+	}
+	return strings.Join(contents, "\n")
+}
+
+// printDiagnostics prints one diagnostic per line in the file:line:column format of compilers
+func printDiagnostics(diagnostics []dsl.Diagnostic) {
+	for _, d := range diagnostics {
+		if d.Severity == dsl.SeverityError {
+			fmt.Println(tui.Critical(d.String()))
+		} else {
+			fmt.Println(tui.Warning(d.String()))
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/Permify/permify-cli/core/assertion"
+	"github.com/Permify/permify-cli/core/cmd/permission"
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/tui"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// watchDebounce is how long the watcher waits for further events before acting, editors often
+// write a file in several steps
+const watchDebounce = 200 * time.Millisecond
+
+// schemaWatcher writes the schema files on every change and runs the assertions against the new version
+type schemaWatcher struct {
+	patterns []string
+	checks   []*assertion.Check
+}
+
+// watch writes the schema once and then on every change of the files until interrupted
+func (w *schemaWatcher) watch() {
+	for _, pattern := range w.patterns {
+		if pattern == "-" {
+			log.Error("--watch can not read the schema from stdin")
+			os.Exit(1)
+		}
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	defer watcher.Close()
+	// directories are watched instead of files, editors often save by replacing the file
+	for _, dir := range w.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	schemaClient := Client()
+	permissionClient := permission.Client()
+	w.push(ctx, schemaClient, permissionClient)
+	log.Info("watching for changes, press ctrl+c to stop")
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-watcher.Events:
+			if event.Op != fsnotify.Chmod && w.matches(event.Name) {
+				timer.Reset(watchDebounce)
+			}
+		case err := <-watcher.Errors:
+			log.Warn("watch error", "error", err)
+		case <-timer.C:
+			w.push(ctx, schemaClient, permissionClient)
+		}
+	}
+}
+
+// dirs returns the directories of the files and of the patterns
+func (w *schemaWatcher) dirs() []string {
+	dirs := []string{}
+	add := func(dir string) {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, pattern := range w.patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			add(filepath.Dir(match))
+		}
+		if dir := filepath.Dir(pattern); !strings.ContainsAny(dir, "*?[") {
+			add(dir)
+		}
+	}
+	return dirs
+}
+
+// matches reports whether a changed file is one of the schema files
+func (w *schemaWatcher) matches(name string) bool {
+	name = filepath.Clean(name)
+	for _, pattern := range w.patterns {
+		if filepath.Clean(pattern) == name {
+			return true
+		}
+		if ok, _ := filepath.Match(filepath.Clean(pattern), name); ok && strings.HasSuffix(name, ".perm") {
+			return true
+		}
+	}
+	return false
+}
+
+// push validates the schema files, writes them when they are valid and runs the assertions.
+// Problems are printed and the watcher keeps running.
+func (w *schemaWatcher) push(ctx context.Context, schemaClient v1.SchemaClient, permissionClient v1.PermissionClient) {
+	fmt.Println(tui.Blue(fmt.Sprintf("--- %s", time.Now().Format(time.TimeOnly))))
+	// the files are read once, so that the validated source is the one written
+	sources, err := readSchemaSources(w.patterns)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	schema, diagnostics, err := parseSchemaSources(sources)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	if schema != nil {
+		diagnostics = dsl.Validate(schema)
+	}
+	if dsl.HasErrors(diagnostics) {
+		printDiagnostics(diagnostics)
+		log.Error("schema is not valid, not written")
+		return
+	}
+
+	writeResponse, err := schemaClient.Write(ctx, &v1.SchemaWriteRequest{
+		TenantId: config.CliConfig.Tenant,
+		Schema:   joinSchemaSources(sources),
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	log.Info("schema written", "version", writeResponse.GetSchemaVersion())
+
+	if len(w.checks) == 0 {
+		return
+	}
+	results := assertion.RunChecks(ctx, permissionClient, config.CliConfig.Tenant, writeResponse.GetSchemaVersion(), w.checks)
+	printResults(results)
+}
+
+// printResults prints the failed assertions and a pass/fail summary
+func printResults(results []assertion.Result) {
+	failed := 0
+	for _, result := range results {
+		if !result.Passed() {
+			failed++
+			fmt.Println(tui.Critical("FAIL " + result.String()))
+		}
+	}
+	summary := fmt.Sprintf("%d passed, %d failed", len(results)-failed, failed)
+	if failed > 0 {
+		fmt.Println(tui.Critical(summary))
+		return
+	}
+	fmt.Println(tui.Blue(summary))
+}
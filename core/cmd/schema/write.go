@@ -2,52 +2,171 @@ package schema
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
+	"github.com/Permify/permify-cli/core/assertion"
 	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
 )
 
-// WriteCmd - implements schema read api
+// WriteCmd - implements schema write api
 type WriteCmd struct {
 	Command string
 }
 
-// Cmd - read command
+// Cmd - write command
 func (wc *WriteCmd) Cmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   wc.Command,
-		Short: "run read request",
+		Short: "run write request",
 		Run:  wc.Run,
 		Args:  cobra.NoArgs,
 	}
 	cmd.SetHelpFunc(utils.CmdHelp)
-	cmd.Flags().StringP("file", "f", "", "perm schema file")
-	cmd.MarkFlagRequired("file")
+	addSchemaFileFlag(cmd, "perm schema files or glob patterns, concatenated in sorted order. Use - to read from stdin")
+	cmd.Flags().Bool("partial", false, "merge the files into the schema of --schema, or the head, instead of replacing it")
+	cmd.Flags().StringSlice("delete", nil, "member to delete in a partial write specified as - <entity>#<member>")
+	cmd.Flags().Bool("ignore-rule-order", false, "write a partial update even when the parameter order of a rule of the current schema is unknown")
+	cmd.Flags().Bool("watch", false, "write the schema again on every change of the files")
+	cmd.Flags().String("assertions", "", "yaml file of permission check assertions to run after every write in watch mode")
+	cmd.MarkFlagsMutuallyExclusive("watch", "partial")
 	return cmd
 }
 
 func (wc *WriteCmd) Run(cmd *cobra.Command, args []string) {
-	file, _ := cmd.Flags().GetString("file")
-	schema, err := utils.ReadFileToString(file)
-	if err != nil {
-		log.Error(err.Error())
+	files, _ := cmd.Flags().GetStringSlice("file")
+	partial, _ := cmd.Flags().GetBool("partial")
+	deletes, _ := cmd.Flags().GetStringSlice("delete")
+	if len(deletes) > 0 && !partial {
+		log.Error("--delete can only be used with --partial")
 		os.Exit(1)
 	}
+	if len(files) == 0 && len(deletes) == 0 {
+		log.Error("required flag \"file\" not set")
+		os.Exit(1)
+	}
+
+	watch, _ := cmd.Flags().GetBool("watch")
+	assertions, _ := cmd.Flags().GetString("assertions")
+	if assertions != "" && !watch {
+		log.Error("--assertions can only be used with --watch")
+		os.Exit(1)
+	}
+	if watch {
+		watcher := &schemaWatcher{patterns: files}
+		if assertions != "" {
+			file, err := assertion.Read(assertions)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+			watcher.checks = file.Checks
+		}
+		watcher.watch()
+		return
+	}
+
+	ctx := context.Background()
+	var schema string
+	if partial {
+		schemaVersion, _ := cmd.Flags().GetString("schema")
+		ignoreRuleOrder, _ := cmd.Flags().GetBool("ignore-rule-order")
+		schema = patchSchema(ctx, schemaVersion, files, deletes, ignoreRuleOrder)
+	} else {
+		source, err := concatSchemaFiles(files)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		schema = source
+	}
 
 	schemaClient := Client()
 	writeRequest := &v1.SchemaWriteRequest{
 		TenantId: config.CliConfig.Tenant,
 		Schema: schema,
 	}
-	writeResponse, err := schemaClient.Write(context.Background(), writeRequest)
+	writeResponse, err := schemaClient.Write(ctx, writeRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(writeResponse)
+	printer.Print(writeResponse)
+}
+
+// concatSchemaFiles joins the contents of the perm files in sorted order
+func concatSchemaFiles(patterns []string) (string, error) {
+	sources, err := readSchemaSources(patterns)
+	if err != nil {
+		return "", err
+	}
+	return joinSchemaSources(sources), nil
+}
+
+// patchSchema applies the perm files and deletes to a schema version and returns the merged perm source.
+// Permify has no partial write api, so the whole schema is written again: changes written in between
+// are lost and the comments of the current schema are not kept. The changes are printed to stderr.
+// Rules of the current schema with an unknown parameter order have to be replaced by the files,
+// otherwise they would be written back with a guessed order, unless ignoreRuleOrder is set.
+func patchSchema(ctx context.Context, version string, files, deletes []string, ignoreRuleOrder bool) string {
+	current, warnings, err := readSchema(ctx, version)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	patch := &dsl.Schema{}
+	if len(files) > 0 {
+		parsed, diagnostics := parseSchemaFiles(files)
+		if parsed == nil {
+			printDiagnostics(diagnostics)
+			os.Exit(1)
+		}
+		patch = parsed
+	}
+	logWarnings(warnings)
+	if unordered := unorderedRules(current, patch); len(unordered) > 0 {
+		log.Warn("rules of the current schema would be written with a guessed parameter order", "rules", strings.Join(unordered, ", "))
+		if !ignoreRuleOrder {
+			log.Error("the parameter order of rules of the current schema is unknown, include the rules in the files or pass --ignore-rule-order")
+			os.Exit(1)
+		}
+	}
+
+	// patch a copy so that current stays as read for the diff
+	merged, err := dsl.Parse("", dsl.Format(current))
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	if err := dsl.Patch(merged, patch, deletes); err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	changes := dsl.Diff(current, merged)
+	if len(changes) == 0 {
+		log.Info("no changes")
+	}
+	for _, change := range changes {
+		fmt.Fprintln(os.Stderr, change.String())
+	}
+	return dsl.Format(merged)
+}
+
+// unorderedRules returns the rules of the current schema with an unknown parameter order that the patch does not replace
+func unorderedRules(current, patch *dsl.Schema) []string {
+	unordered := []string{}
+	for _, rule := range current.Rules {
+		if rule.UnknownParamOrder && patch.Rule(rule.Name) == nil {
+			unordered = append(unordered, rule.Name)
+		}
+	}
+	return unordered
 }
@@ -0,0 +1,147 @@
+package tenancy
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/archive"
+	"github.com/Permify/permify-cli/core/cmd/data"
+	"github.com/Permify/permify-cli/core/cmd/schema"
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/dsl"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// ExportCmd - exports the schema and data of a tenant into an archive
+type ExportCmd struct {
+	Command string
+}
+
+// Cmd - export command
+func (ec *ExportCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   ec.Command,
+		Short: "export a tenant into an archive",
+		Run:   ec.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().StringP("file", "f", "", "archive file to write")
+	cmd.Flags().StringP("tenant", "t", "", "tenant to export. Defaults to the tenant of the profile")
+	cmd.Flags().String("schema-file", "", "perm schema file stored as the schema source of the archive instead of the source printed from the schema")
+	cmd.Flags().Bool("ignore-rule-order", false, "export even when the parameter order of a rule is unknown, the printed source may then bind rule arguments to the wrong parameters")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (ec *ExportCmd) Run(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	schemaFile, _ := cmd.Flags().GetString("schema-file")
+	schemaVersion, _ := cmd.Flags().GetString("schema")
+	tenant, _ := cmd.Flags().GetString("tenant")
+	if tenant == "" {
+		tenant = config.CliConfig.Tenant
+	}
+
+	snapshot := &archive.Archive{
+		Manifest: archive.Manifest{
+			Tenant:        tenant,
+			SchemaVersion: schemaVersion,
+			CreatedAt:     time.Now().UTC(),
+		},
+	}
+	if schemaFile != "" {
+		source, err := utils.ReadFileToString(schemaFile)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		snapshot.Schema = source
+	}
+
+	ctx := context.Background()
+	readResponse, err := schema.Client().Read(ctx, &v1.SchemaReadRequest{
+		TenantId: tenant,
+		Metadata: &v1.SchemaReadRequestMetadata{
+			SchemaVersion: schemaVersion,
+		},
+	})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	snapshot.SchemaDefinition = readResponse.Schema
+	if snapshot.Schema == "" {
+		source, warnings, err := dsl.FromDefinition(readResponse.Schema)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			log.Warn(warning.Message, "code", warning.Code)
+		}
+		if ignoreRuleOrder, _ := cmd.Flags().GetBool("ignore-rule-order"); len(warnings) > 0 && !ignoreRuleOrder {
+			log.Error("the schema source can not be printed reliably, pass the source with --schema-file or --ignore-rule-order")
+			os.Exit(1)
+		}
+		snapshot.Schema = dsl.Format(source)
+	}
+
+	dataClient := data.Client()
+	for _, entityType := range entityTypes(readResponse.Schema) {
+		err = data.ReadAllRelationships(ctx, dataClient, &v1.RelationshipReadRequest{
+			TenantId: tenant,
+			Metadata: &v1.RelationshipReadRequestMetadata{},
+			Filter: &v1.TupleFilter{
+				Entity: &v1.EntityFilter{Type: entityType},
+			},
+		}, func(response *v1.RelationshipReadResponse) error {
+			snapshot.Tuples = append(snapshot.Tuples, response.Tuples...)
+			return nil
+		})
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+
+		err = data.ReadAllAttributes(ctx, dataClient, &v1.AttributeReadRequest{
+			TenantId: tenant,
+			Metadata: &v1.AttributeReadRequestMetadata{},
+			Filter: &v1.AttributeFilter{
+				Entity: &v1.EntityFilter{Type: entityType},
+			},
+		}, func(response *v1.AttributeReadResponse) error {
+			snapshot.Attributes = append(snapshot.Attributes, response.Attributes...)
+			return nil
+		})
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		log.Debug("exported entity type", "type", entityType, "relationships", len(snapshot.Tuples), "attributes", len(snapshot.Attributes))
+	}
+
+	err = archive.Write(file, snapshot)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	printer.Print(snapshot.Manifest)
+}
+
+// entityTypes returns the sorted entity names of a schema
+func entityTypes(definition *v1.SchemaDefinition) []string {
+	types := []string{}
+	for name := range definition.GetEntityDefinitions() {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,239 @@
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Permify/permify-cli/core/archive"
+	"github.com/Permify/permify-cli/core/cmd/data"
+	"github.com/Permify/permify-cli/core/cmd/schema"
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/core/printer"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// ImportCmd - imports an archive created by export into a tenant
+type ImportCmd struct {
+	Command string
+}
+
+// importState records the progress of an import so that it can be resumed
+type importState struct {
+	Tenant        string `json:"tenant"`
+	SchemaVersion string `json:"schema_version"`
+	Relationships int    `json:"relationships"`
+	Attributes    int    `json:"attributes"`
+}
+
+// ImportSummary is printed after an import
+type ImportSummary struct {
+	Tenant        string `json:"tenant"`
+	SchemaVersion string `json:"schema_version"`
+	Relationships int    `json:"relationships"`
+	Attributes    int    `json:"attributes"`
+}
+
+// Cmd - import command
+func (ic *ImportCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   ic.Command,
+		Short: "import an archive into a tenant",
+		Run:   ic.Run,
+		Args:  cobra.NoArgs,
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().StringP("file", "f", "", "archive file created by tenant export")
+	cmd.Flags().StringP("tenant", "t", "", "tenant to import into. Defaults to the tenant of the profile")
+	cmd.Flags().Bool("create-tenant", false, "create the tenant before importing, an existing tenant without a schema is used as it is")
+	cmd.Flags().String("schema-file", "", "perm schema file to write instead of the schema source of the archive")
+	cmd.Flags().Int("batch-size", data.DefaultBatchSize, "number of tuples and attributes sent in a single write request")
+	cmd.Flags().Int("concurrency", 4, "number of write requests sent in parallel")
+	cmd.Flags().Bool("resume", false, "continue an interrupted import from its state file")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func (ic *ImportCmd) Run(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	schemaFile, _ := cmd.Flags().GetString("schema-file")
+	createTenant, _ := cmd.Flags().GetBool("create-tenant")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	resume, _ := cmd.Flags().GetBool("resume")
+	tenant, _ := cmd.Flags().GetString("tenant")
+	if tenant == "" {
+		tenant = config.CliConfig.Tenant
+	}
+
+	snapshot, err := archive.Read(file)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	stateFile := file + ".state"
+	state := &importState{Tenant: tenant}
+	if resume {
+		state, err = readImportState(stateFile, tenant)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		log.Info("resuming import", "schema_version", state.SchemaVersion, "relationships", state.Relationships, "attributes", state.Attributes)
+	}
+
+	ctx := context.Background()
+	if createTenant && state.SchemaVersion == "" {
+		err = createEmptyTenant(ctx, tenant)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// the schema is written first so that the data is validated against it
+	if state.SchemaVersion == "" {
+		source := snapshot.Schema
+		if schemaFile != "" {
+			source, err = utils.ReadFileToString(schemaFile)
+			if err != nil {
+				log.Error(err.Error())
+				os.Exit(1)
+			}
+		}
+		if source == "" {
+			log.Error("the archive holds no schema source, pass --schema-file to import it")
+			os.Exit(1)
+		}
+		writeResponse, err := schema.Client().Write(ctx, &v1.SchemaWriteRequest{
+			TenantId: tenant,
+			Schema:   source,
+		})
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		state.SchemaVersion = writeResponse.SchemaVersion
+		saveImportState(stateFile, state)
+	}
+
+	dataClient := data.Client()
+	progress := tui.NewProgress("importing data", len(snapshot.Tuples)+len(snapshot.Attributes))
+	progress.Add(state.Relationships + state.Attributes)
+	opts := data.BulkWriteOptions{
+		TenantID:      tenant,
+		SchemaVersion: state.SchemaVersion,
+		BatchSize:     batchSize,
+		Concurrency:   concurrency,
+		Progress:      progress,
+	}
+
+	// data is written in chunks of concurrent batches, the state is saved after every chunk
+	chunkSize := max(batchSize, 1) * max(concurrency, 1)
+	for state.Relationships < len(snapshot.Tuples) {
+		end := min(state.Relationships+chunkSize, len(snapshot.Tuples))
+		_, err = data.BulkWrite(ctx, dataClient, &datafile.Data{Tuples: snapshot.Tuples[state.Relationships:end]}, opts)
+		if err != nil {
+			progress.Finish()
+			log.Error(err.Error())
+			log.Info("rerun the import with --resume to continue")
+			os.Exit(1)
+		}
+		state.Relationships = end
+		saveImportState(stateFile, state)
+	}
+	for state.Attributes < len(snapshot.Attributes) {
+		end := min(state.Attributes+chunkSize, len(snapshot.Attributes))
+		_, err = data.BulkWrite(ctx, dataClient, &datafile.Data{Attributes: snapshot.Attributes[state.Attributes:end]}, opts)
+		if err != nil {
+			progress.Finish()
+			log.Error(err.Error())
+			log.Info("rerun the import with --resume to continue")
+			os.Exit(1)
+		}
+		state.Attributes = end
+		saveImportState(stateFile, state)
+	}
+	progress.Finish()
+
+	err = os.Remove(stateFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warn("failed to remove import state", "file", stateFile, "error", err)
+	}
+	printer.Print(ImportSummary{
+		Tenant:        tenant,
+		SchemaVersion: state.SchemaVersion,
+		Relationships: state.Relationships,
+		Attributes:    state.Attributes,
+	})
+}
+
+// createEmptyTenant creates the tenant. A tenant that exists without a schema, e.g. one created by
+// an import that failed before writing the schema, is used as it is, as it can not hold any data.
+func createEmptyTenant(ctx context.Context, tenant string) error {
+	_, createErr := Client().Create(ctx, &v1.TenantCreateRequest{
+		Id:   tenant,
+		Name: tenant,
+	})
+	if createErr == nil {
+		return nil
+	}
+	exists := false
+	err := ListAll(ctx, Client(), &v1.TenantListRequest{}, func(response *v1.TenantListResponse) error {
+		for _, t := range response.Tenants {
+			exists = exists || t.Id == tenant
+		}
+		return nil
+	})
+	if err != nil || !exists {
+		return createErr
+	}
+	_, err = schema.Client().Read(ctx, &v1.SchemaReadRequest{
+		TenantId: tenant,
+		Metadata: &v1.SchemaReadRequestMetadata{},
+	})
+	if err == nil {
+		return fmt.Errorf("tenant %s already exists and has a schema, import into it without --create-tenant", tenant)
+	}
+	if status.Code(err) != codes.NotFound {
+		return err
+	}
+	log.Info("importing into the existing empty tenant", "tenant", tenant)
+	return nil
+}
+
+func readImportState(stateFile, tenant string) (*importState, error) {
+	content, err := os.ReadFile(stateFile)
+	if err != nil {
+		return nil, errors.New("no interrupted import found to resume")
+	}
+	state := &importState{}
+	err = json.Unmarshal(content, state)
+	if err != nil {
+		return nil, err
+	}
+	if state.Tenant != tenant {
+		return nil, errors.New("the interrupted import was started for tenant " + state.Tenant)
+	}
+	return state, nil
+}
+
+func saveImportState(stateFile string, state *importState) {
+	content, _ := json.Marshal(state)
+	err := os.WriteFile(stateFile, content, fs.FileMode(0600))
+	if err != nil {
+		log.Warn("failed to save import state, the import can not be resumed", "error", err)
+	}
+}
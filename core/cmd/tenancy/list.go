@@ -7,10 +7,14 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
+	"github.com/Permify/permify-cli/core/printer"
 	"github.com/Permify/permify-cli/utils"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
 )
 
+// defaultPageSize is the page size used when all pages of tenants are requested
+const defaultPageSize = 100
+
 // ListCmd - implements tenant list api
 type ListCmd struct {
 	Command string
@@ -25,16 +29,73 @@ func (lc *ListCmd) Cmd() *cobra.Command {
 		Args:  cobra.NoArgs,
 	}
 	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().Uint32("page-size", 0, "number of tenants per page. Uses the server default when not set")
+	cmd.Flags().String("continuous-token", "", "continuous token returned by the previous page")
+	cmd.Flags().Bool("all", false, "follow the continuous tokens and print every page as it arrives. json, yaml, table and csv are printed once after the last page")
+	cmd.MarkFlagsMutuallyExclusive("all", "continuous-token")
 	return cmd
 }
 
 func (lc *ListCmd) Run(cmd *cobra.Command, args []string) {
+	pageSize, _ := cmd.Flags().GetUint32("page-size")
+	continuousToken, _ := cmd.Flags().GetString("continuous-token")
+	all, _ := cmd.Flags().GetBool("all")
+
 	tenancyClient := Client()
-	listRequest := &v1.TenantListRequest{}
+	listRequest := &v1.TenantListRequest{
+		PageSize:        pageSize,
+		ContinuousToken: continuousToken,
+	}
+	if all {
+		// formats printing a single document need every page first
+		allResponse := &v1.TenantListResponse{}
+		err := ListAll(context.Background(), tenancyClient, listRequest, func(response *v1.TenantListResponse) error {
+			if printer.Streaming() {
+				response.ContinuousToken = ""
+				printer.Print(response)
+				return nil
+			}
+			allResponse.Tenants = append(allResponse.Tenants, response.Tenants...)
+			return nil
+		})
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+		if !printer.Streaming() {
+			printer.Print(allResponse)
+		}
+		return
+	}
+
 	listResponse, err := tenancyClient.List(context.Background(), listRequest)
 	if err != nil {
 		log.Error(err.Error())
 		os.Exit(1)
 	}
-	utils.PrettyPrint(listResponse)
+	printer.Print(listResponse)
+	if listResponse.ContinuousToken != "" && len(listResponse.Tenants) > 0 {
+		log.Info("more tenants available, pass --continuous-token or --all to list them", "continuous_token", listResponse.ContinuousToken)
+	}
+}
+
+// ListAll calls fn for every page of tenants, following the continuous tokens until the last page
+func ListAll(ctx context.Context, tenancyClient v1.TenancyClient, request *v1.TenantListRequest, fn func(*v1.TenantListResponse) error) error {
+	if request.PageSize == 0 {
+		request.PageSize = defaultPageSize
+	}
+	for {
+		response, err := tenancyClient.List(ctx, request)
+		if err != nil {
+			return err
+		}
+		err = fn(response)
+		if err != nil {
+			return err
+		}
+		if response.ContinuousToken == "" || len(response.Tenants) == 0 {
+			return nil
+		}
+		request.ContinuousToken = response.ContinuousToken
+	}
 }
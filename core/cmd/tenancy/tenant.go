@@ -20,10 +20,14 @@ func New() *cobra.Command {
 	createCmd := CreateCmd{"create"}
 	deleteCmd := DeleteCmd{"delete"}
 	listCmd := ListCmd{"list"}
+	exportCmd := ExportCmd{"export"}
+	importCmd := ImportCmd{"import"}
 
 	tenancyCmd.AddCommand(createCmd.Cmd())
 	tenancyCmd.AddCommand(deleteCmd.Cmd())
 	tenancyCmd.AddCommand(listCmd.Cmd())
+	tenancyCmd.AddCommand(exportCmd.Cmd())
+	tenancyCmd.AddCommand(importCmd.Cmd())
 
 	return tenancyCmd
 }
@@ -0,0 +1,159 @@
+// Package test is cli command for running assertion tests of a schema against a throwaway tenant
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/assertion"
+	"github.com/Permify/permify-cli/core/cmd/data"
+	"github.com/Permify/permify-cli/core/cmd/permission"
+	"github.com/Permify/permify-cli/core/cmd/schema"
+	"github.com/Permify/permify-cli/core/cmd/tenancy"
+	"github.com/Permify/permify-cli/core/datafile"
+	"github.com/Permify/permify-cli/tui"
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// TestCmd - runs the assertions of a test file against a throwaway tenant
+type TestCmd struct {
+	Command string
+}
+
+// New - Creates new test command
+func New() *cobra.Command {
+	testCmd := TestCmd{"test"}
+	return testCmd.Cmd()
+}
+
+// Cmd - test command
+func (tc *TestCmd) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   tc.Command + " <file>",
+		Short: "run the assertions of a test file against a throwaway tenant",
+		Run:   tc.Run,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.SetHelpFunc(utils.CmdHelp)
+	cmd.Flags().String("junit", "", "write a junit xml report to the file")
+	cmd.Flags().Bool("keep-tenant", false, "do not delete the tenant after the run")
+	return cmd
+}
+
+func (tc *TestCmd) Run(cmd *cobra.Command, args []string) {
+	file := args[0]
+	junit, _ := cmd.Flags().GetString("junit")
+	keepTenant, _ := cmd.Flags().GetBool("keep-tenant")
+
+	suite, err := assertion.ReadSuite(file)
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	suiteData, err := suite.Data()
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	tenant := fmt.Sprintf("permctl-test-%d", time.Now().UnixNano())
+	tenancyClient := tenancy.Client()
+	_, err = tenancyClient.Create(ctx, &v1.TenantCreateRequest{Id: tenant, Name: tenant})
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+	log.Debug("created test tenant", "tenant", tenant)
+
+	results, err := run(ctx, tenant, suite, suiteData)
+	if keepTenant {
+		log.Info("kept test tenant", "tenant", tenant)
+	} else if _, deleteErr := tenancyClient.Delete(ctx, &v1.TenantDeleteRequest{Id: tenant}); deleteErr != nil {
+		log.Warn("could not delete test tenant", "tenant", tenant, "error", deleteErr)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		os.Exit(1)
+	}
+
+	failed := printReport(results)
+	if junit != "" {
+		if err := writeJUnit(junit, file, results); err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// run writes the schema and data of the suite into the tenant and runs its assertions
+func run(ctx context.Context, tenant string, suite *assertion.Suite, suiteData *datafile.Data) ([]assertion.Result, error) {
+	writeResponse, err := schema.Client().Write(ctx, &v1.SchemaWriteRequest{
+		TenantId: tenant,
+		Schema:   suite.Schema,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schema write failed: %w", err)
+	}
+	schemaVersion := writeResponse.GetSchemaVersion()
+
+	if len(suiteData.Tuples)+len(suiteData.Attributes) > 0 {
+		progress := tui.NewProgress("writing data", len(suiteData.Tuples)+len(suiteData.Attributes))
+		_, err = data.BulkWrite(ctx, data.Client(), suiteData, data.BulkWriteOptions{
+			TenantID:      tenant,
+			SchemaVersion: schemaVersion,
+			Progress:      progress,
+		})
+		progress.Finish()
+		if err != nil {
+			return nil, fmt.Errorf("data write failed: %w", err)
+		}
+	}
+	return suite.Run(ctx, permission.Client(), tenant, schemaVersion), nil
+}
+
+// printReport prints every assertion under its scenario and a summary, it returns the number of failed assertions
+func printReport(results []assertion.Result) int {
+	failed := 0
+	scenario := ""
+	for i, result := range results {
+		if i == 0 || result.Scenario != scenario {
+			scenario = result.Scenario
+			fmt.Println(tui.Pink(scenario))
+		}
+		if result.Passed() {
+			fmt.Println(tui.Blue("  PASS " + result.Name))
+			continue
+		}
+		failed++
+		fmt.Println(tui.Critical("  FAIL " + result.String()))
+	}
+	summary := fmt.Sprintf("%d passed, %d failed", len(results)-failed, failed)
+	if failed > 0 {
+		fmt.Println(tui.Critical(summary))
+	} else {
+		fmt.Println(tui.Blue(summary))
+	}
+	return failed
+}
+
+func writeJUnit(path, name string, results []assertion.Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := assertion.WriteJUnit(f, name, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -7,13 +7,23 @@ import (
 	"os"
 	"strings"
 
-	"github.com/Permify/permify-cli/core/logger"
 	"gopkg.in/yaml.v3"
 )
 
 // CliConfig is the global config variable
 var CliConfig = CoreConfig{}
 
+// OfflineAnnotation marks commands that run without a permify connection, the config
+// is not required for them and is only loaded when it exists
+const OfflineAnnotation = "permctl/offline"
+
+// ExitCodeAnnotation is the exit code of a command when its flags or its setup fail. When the command
+// sets ExitCodeFlagsAnnotation, a comma separated list of boolean flags, it applies only when one of them is set
+const (
+	ExitCodeAnnotation      = "permctl/exit-code"
+	ExitCodeFlagsAnnotation = "permctl/exit-code-flags"
+)
+
 var profileConfigs = ProfileConfigs{}
 
 // ProfileConfigs stores configs for all profiles
@@ -25,9 +35,31 @@ type ProfileConfigs struct {
 
 // CoreConfig is the config struct
 type CoreConfig struct {
-	PermifyURL			 string  `yaml:"permify_url"`
-	Tenant 				 string  `yaml:"tenant"`
-	SslEnabled           bool    `yaml:"-"`
+	PermifyURL string    `yaml:"permify_url"`
+	Tenant     string    `yaml:"tenant"`
+	SslEnabled bool      `yaml:"-"`
+	TLS        TLSConfig `yaml:"tls,omitempty"`
+	// Token is sent as a bearer token on every request. TokenFile and
+	// TokenCommand are only consulted when Token is empty, in that order.
+	Token        string `yaml:"token,omitempty"`
+	TokenFile    string `yaml:"token_file,omitempty"`
+	TokenCommand string `yaml:"token_command,omitempty"`
+	// SnapToken is the snap token of the last data write run with --save-snap-token
+	SnapToken string `yaml:"snap_token,omitempty"`
+}
+
+// TLSConfig holds the transport security settings of a profile
+type TLSConfig struct {
+	CACert             string `yaml:"ca_cert,omitempty"`
+	ClientCert         string `yaml:"client_cert,omitempty"`
+	ClientKey          string `yaml:"client_key,omitempty"`
+	ServerName         string `yaml:"server_name,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
+}
+
+// Enabled reports whether any tls setting has been configured for the profile
+func (t TLSConfig) Enabled() bool {
+	return t.CACert != "" || t.ClientCert != "" || t.ClientKey != "" || t.ServerName != "" || t.InsecureSkipVerify
 }
 
 // IsConfigured checks if permctl cli has been configured
@@ -42,7 +74,7 @@ func IsConfigured(file string, profile string) error {
 	}
 	err = yaml.Unmarshal(data, &profileConfigs.Configs)
 	if err != nil {
-		logger.Log.Fatal("Error unmarshaling yaml")
+		return fmt.Errorf("error unmarshaling yaml of %s: %w", file, err)
 	}
 	if profileConfigs.Configs[profile].PermifyURL == "" {
 		return fmt.Errorf("permify url is empty for profile %s", profile)
@@ -65,12 +97,12 @@ func Load(file string, profile string) error {
 	}
 	err = yaml.Unmarshal(data, &profileConfigs.Configs)
 	if err != nil {
-		logger.Log.Fatal("Error unmarshaling yaml")
+		return fmt.Errorf("error unmarshaling yaml of %s: %w", file, err)
 	}
 	profileConfigs.File = file
 	profileConfigs.Profile = profile
 	CliConfig = profileConfigs.Configs[profile]
-	CliConfig.SslEnabled = strings.HasPrefix(CliConfig.PermifyURL, "https")
+	CliConfig.SslEnabled = strings.HasPrefix(CliConfig.PermifyURL, "https") || CliConfig.TLS.Enabled()
 	return err
 }
 
@@ -84,7 +116,7 @@ func New(file string, profile string) error {
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(file, newConfigDataByte, fs.FileMode(0644))
+	err = os.WriteFile(file, newConfigDataByte, fs.FileMode(0600))
 	return err
 }
 
@@ -100,6 +132,12 @@ func Write() error {
 	if err != nil {
 		return err
 	}
-	err = os.WriteFile(profileConfigs.File, newConfigDataByte, fs.FileMode(0644))
+	err = os.WriteFile(profileConfigs.File, newConfigDataByte, fs.FileMode(0600))
 	return err
 }
+
+// SaveSnapToken stores the snap token of a data write in the profile of the config file
+func SaveSnapToken(snapToken string) error {
+	CliConfig.SnapToken = snapToken
+	return Write()
+}
@@ -0,0 +1,320 @@
+// Package datafile reads relationships and attributes from json, yaml, csv and text files
+package datafile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Permify/permify-cli/utils"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/protobuf/types/known/anypb"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a data file
+type Format string
+
+const (
+	// JSON files hold a list of tuples and attributes or an object with tuples and attributes lists
+	JSON Format = "json"
+	// YAML files have the same layout as json files
+	YAML Format = "yaml"
+	// CSV files hold one entity,relation,subject or entity,attribute,type,value record per line
+	CSV Format = "csv"
+	// Text files hold one <type>:<id>#<relation>@<type>:<id> tuple
+	// or <type>:<id>$<attribute>|<value type>:<value> attribute per line
+	Text Format = "text"
+)
+
+// Stdin is the file name used to read data from standard input
+const Stdin = "-"
+
+// Data holds the relationships and attributes read from a file
+type Data struct {
+	Tuples     []*v1.Tuple
+	Attributes []*v1.Attribute
+}
+
+// Read loads the data file at path, or standard input when path is "-".
+// The format is detected from the file extension or content when it is empty.
+func Read(path string, format Format) (*Data, error) {
+	var content []byte
+	var err error
+	if path == Stdin {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = DetectFormat(path, content)
+	}
+	return Parse(content, format)
+}
+
+// Parse decodes content in the given format
+func Parse(content []byte, format Format) (*Data, error) {
+	switch format {
+	case JSON, YAML:
+		return parseDocument(content)
+	case CSV:
+		return parseCSV(content)
+	case Text:
+		return parseText(content)
+	}
+	return nil, fmt.Errorf("unknown data file format %q, must be one of json, yaml, csv, text", format)
+}
+
+// DetectFormat guesses the format from the file extension, falling back to the content
+func DetectFormat(path string, content []byte) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON
+	case ".yaml", ".yml":
+		return YAML
+	case ".csv":
+		return CSV
+	case ".txt":
+		return Text
+	}
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("[")) || bytes.HasPrefix(trimmed, []byte("{")) {
+		return JSON
+	}
+	return Text
+}
+
+// document is the layout of json and yaml data files
+type document struct {
+	Tuples     []entry `yaml:"tuples"`
+	Attributes []entry `yaml:"attributes"`
+}
+
+// entry is a tuple or attribute written either in text notation or as an object
+type entry struct {
+	tuple     *v1.Tuple
+	attribute *v1.Attribute
+}
+
+func (e *entry) UnmarshalYAML(value *yaml.Node) error {
+	var err error
+	if value.Kind == yaml.ScalarNode {
+		e.tuple, e.attribute, err = parseLine(value.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", value.Line, err)
+		}
+		return nil
+	}
+	var fields struct {
+		Entity    string    `yaml:"entity"`
+		Relation  string    `yaml:"relation"`
+		Subject   string    `yaml:"subject"`
+		Attribute string    `yaml:"attribute"`
+		Type      string    `yaml:"type"`
+		Value     yaml.Node `yaml:"value"`
+	}
+	err = value.Decode(&fields)
+	if err != nil {
+		return err
+	}
+	if fields.Attribute != "" {
+		e.attribute, err = newAttribute(fields.Entity, fields.Attribute, fields.Type, &fields.Value)
+	} else {
+		e.tuple, err = newTuple(fields.Entity, fields.Relation, fields.Subject)
+	}
+	if err != nil {
+		return fmt.Errorf("line %d: %w", value.Line, err)
+	}
+	return nil
+}
+
+func parseDocument(content []byte) (*Data, error) {
+	var root yaml.Node
+	err := yaml.Unmarshal(content, &root)
+	if err != nil {
+		return nil, err
+	}
+	data := &Data{}
+	if len(root.Content) == 0 {
+		return data, nil
+	}
+
+	doc := document{}
+	if root.Content[0].Kind == yaml.SequenceNode {
+		err = root.Content[0].Decode(&doc.Tuples)
+	} else {
+		err = root.Content[0].Decode(&doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range append(doc.Tuples, doc.Attributes...) {
+		data.add(e.tuple, e.attribute)
+	}
+	return data, nil
+}
+
+func parseCSV(content []byte) (*Data, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	data := &Data{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.EqualFold(record[0], "entity") {
+			continue
+		}
+		var tuple *v1.Tuple
+		var attribute *v1.Attribute
+		switch len(record) {
+		case 1:
+			tuple, attribute, err = parseLine(strings.TrimSpace(record[0]))
+		case 3:
+			tuple, err = newTuple(record[0], record[1], record[2])
+		case 4:
+			attribute, err = utils.NewAttribute(record[0], record[1], record[2], record[3])
+		default:
+			err = errors.New("expected entity,relation,subject or entity,attribute,type,value columns")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		data.add(tuple, attribute)
+	}
+	return data, nil
+}
+
+func parseText(content []byte) (*Data, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	data := &Data{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//") {
+			continue
+		}
+		tuple, attribute, err := parseLine(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		data.add(tuple, attribute)
+	}
+	return data, scanner.Err()
+}
+
+func (d *Data) add(tuple *v1.Tuple, attribute *v1.Attribute) {
+	if tuple != nil {
+		d.Tuples = append(d.Tuples, tuple)
+	}
+	if attribute != nil {
+		d.Attributes = append(d.Attributes, attribute)
+	}
+}
+
+// parseLine parses a tuple or, when it contains a $, an attribute in text notation
+func parseLine(line string) (*v1.Tuple, *v1.Attribute, error) {
+	if strings.Contains(line, "$") {
+		attribute, err := utils.ParseAttribute(line)
+		return nil, attribute, err
+	}
+	tuple, err := utils.ParseTuple(line)
+	return tuple, nil, err
+}
+
+// newAttribute builds an attribute from a yaml value. The value type is inferred
+// from the yaml value when it is not set explicitly, a missing or null value is an error.
+func newAttribute(entityStr, attribute, valueType string, value *yaml.Node) (*v1.Attribute, error) {
+	entity, err := utils.ParseEntity(strings.TrimSpace(entityStr))
+	if err != nil {
+		return nil, err
+	}
+	if value.Kind == 0 || value.ShortTag() == "!!null" {
+		return nil, fmt.Errorf("attribute %s has no value", attribute)
+	}
+	if valueType == "" {
+		valueType = inferType(value)
+	}
+
+	var anyValue *anypb.Any
+	if value.Kind == yaml.SequenceNode {
+		items := []string{}
+		for _, item := range value.Content {
+			items = append(items, item.Value)
+		}
+		if !strings.HasSuffix(valueType, "[]") {
+			return nil, fmt.Errorf("array value given for attribute type %s", valueType)
+		}
+		anyValue, err = utils.NewAttributeArrayValue(valueType, items)
+	} else {
+		anyValue, err = utils.NewAttributeValue(valueType, value.Value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Attribute{
+		Entity:    entity,
+		Attribute: attribute,
+		Value:     anyValue,
+	}, nil
+}
+
+func inferType(value *yaml.Node) string {
+	node := value
+	suffix := ""
+	if value.Kind == yaml.SequenceNode {
+		if len(value.Content) == 0 {
+			return "string[]"
+		}
+		node, suffix = value.Content[0], "[]"
+	}
+	switch node.ShortTag() {
+	case "!!bool":
+		return "boolean" + suffix
+	case "!!int":
+		return "integer" + suffix
+	case "!!float":
+		return "double" + suffix
+	}
+	return "string" + suffix
+}
+
+func newTuple(entityStr, relation, subjectStr string) (*v1.Tuple, error) {
+	entity, err := utils.ParseEntity(strings.TrimSpace(entityStr))
+	if err != nil {
+		return nil, err
+	}
+	relation = strings.TrimSpace(relation)
+	if relation == "" {
+		return nil, errors.New("relation must not be empty")
+	}
+	subject, err := utils.ParseSubject(strings.TrimSpace(subjectStr))
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Tuple{
+		Entity:   entity,
+		Relation: relation,
+		Subject:  subject,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema is a parsed perm file
+type Schema struct {
+	File     string
+	Entities []*Entity
+	Rules    []*Rule
+}
+
+// Entity is an entity definition with its relations, attributes and permissions in source order
+type Entity struct {
+	Pos         Position
+	Name        string
+	Relations   []*Relation
+	Attributes  []*Attribute
+	Permissions []*Permission
+}
+
+// Relation is a relation definition, e.g. relation member @user @team#member
+type Relation struct {
+	Pos   Position
+	Name  string
+	Types []*RelationType
+}
+
+// RelationType is an allowed subject of a relation, Relation is empty for plain entities
+type RelationType struct {
+	Pos      Position
+	Type     string
+	Relation string
+}
+
+func (t *RelationType) String() string {
+	if t.Relation == "" {
+		return "@" + t.Type
+	}
+	return fmt.Sprintf("@%s#%s", t.Type, t.Relation)
+}
+
+// AttributeTypes lists the types of attributes and rule parameters in the order of the permify attribute type enum
+var AttributeTypes = []string{"boolean", "boolean[]", "string", "string[]", "integer", "integer[]", "double", "double[]"}
+
+// Attribute is an attribute definition, array types end with []
+type Attribute struct {
+	Pos  Position
+	Name string
+	Type string
+}
+
+// Permission is a permission or action definition
+type Permission struct {
+	Pos  Position
+	Name string
+	Expr Expr
+}
+
+// Rule is a rule definition, the body is the raw cel expression
+type Rule struct {
+	Pos    Position
+	Name   string
+	Params []*Param
+	Body   string
+	// UnknownParamOrder is set for rules read from permify whose parameter order could not be
+	// recovered, their parameters are sorted by name. See FromDefinition.
+	UnknownParamOrder bool
+}
+
+// Param is a rule parameter
+type Param struct {
+	Pos  Position
+	Name string
+	Type string
+}
+
+// Expr is a permission expression
+type Expr interface {
+	Position() Position
+	String() string
+}
+
+// Operators of a BinaryExpr
+const (
+	OpOr  = "or"
+	OpAnd = "and"
+	OpNot = "not"
+)
+
+// BinaryExpr combines two expressions with or, and or not (exclusion)
+type BinaryExpr struct {
+	Pos   Position
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+func (e *BinaryExpr) Position() Position { return e.Pos }
+
+func (e *BinaryExpr) String() string {
+	return fmt.Sprintf("%s %s %s", operand(e.Left, e.Op, false), e.Op, operand(e.Right, e.Op, true))
+}
+
+// operand wraps nested expressions in parentheses where the precedence requires it
+func operand(e Expr, op string, right bool) string {
+	nested, ok := e.(*BinaryExpr)
+	if !ok {
+		return e.String()
+	}
+	if precedence(nested.Op) < precedence(op) || (right && precedence(nested.Op) == precedence(op) && (nested.Op != op || op == OpNot)) {
+		return "(" + nested.String() + ")"
+	}
+	return nested.String()
+}
+
+func precedence(op string) int {
+	if op == OpOr {
+		return 1
+	}
+	return 2
+}
+
+// Ref references a relation, permission or attribute of the entity, or with a
+// member the relation or permission of the entities related through Name, e.g. parent.view
+type Ref struct {
+	Pos    Position
+	Name   string
+	Member string
+}
+
+func (e *Ref) Position() Position { return e.Pos }
+
+func (e *Ref) String() string {
+	if e.Member == "" {
+		return e.Name
+	}
+	return e.Name + "." + e.Member
+}
+
+// CallExpr calls a rule with attributes or request context values, e.g. check_credit(credit)
+type CallExpr struct {
+	Pos  Position
+	Rule string
+	Args []*Ref
+}
+
+func (e *CallExpr) Position() Position { return e.Pos }
+
+func (e *CallExpr) String() string {
+	args := []string{}
+	for _, arg := range e.Args {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("%s(%s)", e.Rule, strings.Join(args, ", "))
+}
+
+// Walk calls fn for every reference and call in the expression
+func Walk(e Expr, fn func(Expr)) {
+	switch e := e.(type) {
+	case *BinaryExpr:
+		Walk(e.Left, fn)
+		Walk(e.Right, fn)
+	default:
+		fn(e)
+	}
+}
+
+// Entity returns the entity with the given name or nil
+func (s *Schema) Entity(name string) *Entity {
+	for _, entity := range s.Entities {
+		if entity.Name == name {
+			return entity
+		}
+	}
+	return nil
+}
+
+// Rule returns the rule with the given name or nil
+func (s *Schema) Rule(name string) *Rule {
+	for _, rule := range s.Rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Relation returns the relation with the given name or nil
+func (e *Entity) Relation(name string) *Relation {
+	for _, relation := range e.Relations {
+		if relation.Name == name {
+			return relation
+		}
+	}
+	return nil
+}
+
+// Attribute returns the attribute with the given name or nil
+func (e *Entity) Attribute(name string) *Attribute {
+	for _, attribute := range e.Attributes {
+		if attribute.Name == name {
+			return attribute
+		}
+	}
+	return nil
+}
+
+// Permission returns the permission with the given name or nil
+func (e *Entity) Permission(name string) *Permission {
+	for _, permission := range e.Permissions {
+		if permission.Name == name {
+			return permission
+		}
+	}
+	return nil
+}
+
+// Param returns the parameter with the given name or nil
+func (r *Rule) Param(name string) *Param {
+	for _, param := range r.Params {
+		if param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
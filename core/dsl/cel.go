@@ -0,0 +1,232 @@
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// celOperators maps the function names of cel operators to their source form and precedence,
+// a lower precedence binds tighter
+var celOperators = map[string]struct {
+	symbol     string
+	precedence int
+}{
+	"_?_:_": {"?", 8},
+	"_||_":  {"||", 7},
+	"_&&_":  {"&&", 6},
+	"_==_":  {"==", 5},
+	"_!=_":  {"!=", 5},
+	"_<_":   {"<", 5},
+	"_<=_":  {"<=", 5},
+	"_>_":   {">", 5},
+	"_>=_":  {">=", 5},
+	"@in":   {"in", 5},
+	"_+_":   {"+", 4},
+	"_-_":   {"-", 4},
+	"_*_":   {"*", 3},
+	"_/_":   {"/", 3},
+	"_%_":   {"%", 3},
+	"!_":    {"!", 2},
+	"-_":    {"-", 2},
+	"_[_]":  {"[]", 1},
+}
+
+// unparser turns a checked cel expression of a rule back into source
+type unparser struct {
+	macros map[int64]*expr.Expr
+	out    strings.Builder
+}
+
+// unparseCEL returns the source of a checked cel expression, macros like exists or has are
+// restored from the source info so that the result reads like the written rule
+func unparseCEL(checked *expr.CheckedExpr) (string, error) {
+	if checked.GetExpr() == nil {
+		return "", nil
+	}
+	u := &unparser{macros: checked.GetSourceInfo().GetMacroCalls()}
+	err := u.visit(checked.GetExpr())
+	if err != nil {
+		return "", err
+	}
+	return u.out.String(), nil
+}
+
+func (u *unparser) visit(e *expr.Expr) error {
+	if macro, ok := u.macros[e.GetId()]; ok {
+		return u.visitCall(macro.GetCallExpr())
+	}
+	switch kind := e.GetExprKind().(type) {
+	case *expr.Expr_ConstExpr:
+		return u.visitConst(kind.ConstExpr)
+	case *expr.Expr_IdentExpr:
+		u.out.WriteString(kind.IdentExpr.GetName())
+	case *expr.Expr_SelectExpr:
+		if kind.SelectExpr.GetTestOnly() {
+			u.out.WriteString("has(")
+			err := u.visitSelect(kind.SelectExpr)
+			u.out.WriteString(")")
+			return err
+		}
+		return u.visitSelect(kind.SelectExpr)
+	case *expr.Expr_CallExpr:
+		return u.visitCall(kind.CallExpr)
+	case *expr.Expr_ListExpr:
+		u.out.WriteString("[")
+		for i, element := range kind.ListExpr.GetElements() {
+			if i > 0 {
+				u.out.WriteString(", ")
+			}
+			err := u.visit(element)
+			if err != nil {
+				return err
+			}
+		}
+		u.out.WriteString("]")
+	case *expr.Expr_StructExpr:
+		return u.visitStruct(kind.StructExpr)
+	default:
+		return fmt.Errorf("unsupported cel expression %d", e.GetId())
+	}
+	return nil
+}
+
+func (u *unparser) visitConst(c *expr.Constant) error {
+	switch kind := c.GetConstantKind().(type) {
+	case *expr.Constant_NullValue:
+		u.out.WriteString("null")
+	case *expr.Constant_BoolValue:
+		u.out.WriteString(strconv.FormatBool(kind.BoolValue))
+	case *expr.Constant_Int64Value:
+		u.out.WriteString(strconv.FormatInt(kind.Int64Value, 10))
+	case *expr.Constant_Uint64Value:
+		u.out.WriteString(strconv.FormatUint(kind.Uint64Value, 10) + "u")
+	case *expr.Constant_DoubleValue:
+		value := strconv.FormatFloat(kind.DoubleValue, 'g', -1, 64)
+		if !strings.ContainsAny(value, ".eEnN") {
+			value += ".0"
+		}
+		u.out.WriteString(value)
+	case *expr.Constant_StringValue:
+		u.out.WriteString(strconv.Quote(kind.StringValue))
+	case *expr.Constant_BytesValue:
+		u.out.WriteString("b" + strconv.Quote(string(kind.BytesValue)))
+	default:
+		return fmt.Errorf("unsupported cel constant %v", c)
+	}
+	return nil
+}
+
+func (u *unparser) visitSelect(s *expr.Expr_Select) error {
+	err := u.visitOperand(s.GetOperand(), 1, false)
+	if err != nil {
+		return err
+	}
+	u.out.WriteString("." + s.GetField())
+	return nil
+}
+
+func (u *unparser) visitCall(call *expr.Expr_Call) error {
+	operator, ok := celOperators[call.GetFunction()]
+	if !ok {
+		if call.GetTarget() != nil {
+			err := u.visitOperand(call.GetTarget(), 1, false)
+			if err != nil {
+				return err
+			}
+			u.out.WriteString(".")
+		}
+		u.out.WriteString(call.GetFunction() + "(")
+		for i, arg := range call.GetArgs() {
+			if i > 0 {
+				u.out.WriteString(", ")
+			}
+			err := u.visit(arg)
+			if err != nil {
+				return err
+			}
+		}
+		u.out.WriteString(")")
+		return nil
+	}
+
+	args := call.GetArgs()
+	switch {
+	case call.GetFunction() == "_[_]" && len(args) == 2:
+		err := u.visitOperand(args[0], operator.precedence, false)
+		if err != nil {
+			return err
+		}
+		u.out.WriteString("[")
+		err = u.visit(args[1])
+		u.out.WriteString("]")
+		return err
+	case call.GetFunction() == "_?_:_" && len(args) == 3:
+		err := u.visitOperand(args[0], operator.precedence, true)
+		if err != nil {
+			return err
+		}
+		u.out.WriteString(" ? ")
+		err = u.visitOperand(args[1], operator.precedence, true)
+		if err != nil {
+			return err
+		}
+		u.out.WriteString(" : ")
+		return u.visitOperand(args[2], operator.precedence, false)
+	case len(args) == 1:
+		u.out.WriteString(operator.symbol)
+		return u.visitOperand(args[0], operator.precedence, false)
+	case len(args) == 2:
+		err := u.visitOperand(args[0], operator.precedence, false)
+		if err != nil {
+			return err
+		}
+		u.out.WriteString(" " + operator.symbol + " ")
+		return u.visitOperand(args[1], operator.precedence, true)
+	}
+	return fmt.Errorf("unsupported use of cel operator %s", call.GetFunction())
+}
+
+func (u *unparser) visitStruct(s *expr.Expr_CreateStruct) error {
+	u.out.WriteString(s.GetMessageName() + "{")
+	for i, entry := range s.GetEntries() {
+		if i > 0 {
+			u.out.WriteString(", ")
+		}
+		if field, ok := entry.GetKeyKind().(*expr.Expr_CreateStruct_Entry_FieldKey); ok {
+			u.out.WriteString(field.FieldKey)
+		} else {
+			err := u.visit(entry.GetMapKey())
+			if err != nil {
+				return err
+			}
+		}
+		u.out.WriteString(": ")
+		err := u.visit(entry.GetValue())
+		if err != nil {
+			return err
+		}
+	}
+	u.out.WriteString("}")
+	return nil
+}
+
+// visitOperand wraps the operand in parentheses when it binds weaker than its parent,
+// right operands of the same precedence are wrapped as well since operators are left associative
+func (u *unparser) visitOperand(e *expr.Expr, parent int, right bool) error {
+	precedence := 0
+	if _, ok := u.macros[e.GetId()]; !ok {
+		if call, ok := e.GetExprKind().(*expr.Expr_CallExpr); ok {
+			precedence = celOperators[call.CallExpr.GetFunction()].precedence
+		}
+	}
+	if precedence > parent || (right && precedence == parent && precedence > 1) {
+		u.out.WriteString("(")
+		err := u.visit(e)
+		u.out.WriteString(")")
+		return err
+	}
+	return u.visit(e)
+}
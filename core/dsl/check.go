@@ -0,0 +1,353 @@
+package dsl
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Severity of a diagnostic
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in a schema, Code names the check that found it
+type Diagnostic struct {
+	Pos      Position `json:"position"`
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", d.Pos, d.Severity, d.Message, d.Code)
+}
+
+// contextName is the name used to pass request context values to rules, e.g. request.ip_address
+const contextName = "request"
+
+type checker struct {
+	schema      *Schema
+	diagnostics []Diagnostic
+}
+
+func (c *checker) report(pos Position, severity Severity, code, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Pos:      pos,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate returns the errors that make the server reject the schema
+func Validate(schema *Schema) []Diagnostic {
+	c := &checker{schema: schema}
+	c.checkNames()
+	for _, rule := range schema.Rules {
+		for _, param := range rule.Params {
+			if !slices.Contains(AttributeTypes, param.Type) {
+				c.report(param.Pos, SeverityError, "unknown-type", "unknown type %s of parameter %s", param.Type, param.Name)
+			}
+		}
+	}
+	for _, entity := range schema.Entities {
+		for _, relation := range entity.Relations {
+			c.checkRelationTypes(relation)
+		}
+		for _, attribute := range entity.Attributes {
+			if !slices.Contains(AttributeTypes, attribute.Type) {
+				c.report(attribute.Pos, SeverityError, "unknown-type", "unknown type %s of attribute %s", attribute.Type, attribute.Name)
+			}
+		}
+		for _, permission := range entity.Permissions {
+			Walk(permission.Expr, func(e Expr) {
+				switch e := e.(type) {
+				case *Ref:
+					c.checkRef(entity, e)
+				case *CallExpr:
+					c.checkCall(entity, e)
+				}
+			})
+		}
+		c.checkCycles(entity)
+	}
+	return sortDiagnostics(c.diagnostics)
+}
+
+// Lint returns the errors of Validate and warnings about unused and shadowed definitions
+func Lint(schema *Schema) []Diagnostic {
+	c := &checker{schema: schema, diagnostics: Validate(schema)}
+	c.checkUnused()
+	c.checkShadowed()
+	return sortDiagnostics(c.diagnostics)
+}
+
+// checkNames reports entities, rules and entity members defined more than once
+func (c *checker) checkNames() {
+	defined := map[string]Position{}
+	define := func(key, kind, name string, pos Position) {
+		if first, ok := defined[key]; ok {
+			c.report(pos, SeverityError, "duplicate-name", "%s %s is already defined at %s", kind, name, first)
+			return
+		}
+		defined[key] = pos
+	}
+	for _, entity := range c.schema.Entities {
+		define("entity "+entity.Name, "entity", entity.Name, entity.Pos)
+		for _, relation := range entity.Relations {
+			define(entity.Name+"#"+relation.Name, "relation", relation.Name, relation.Pos)
+		}
+		for _, attribute := range entity.Attributes {
+			define(entity.Name+"#"+attribute.Name, "attribute", attribute.Name, attribute.Pos)
+		}
+		for _, permission := range entity.Permissions {
+			define(entity.Name+"#"+permission.Name, "permission", permission.Name, permission.Pos)
+		}
+	}
+	for _, rule := range c.schema.Rules {
+		define("rule "+rule.Name, "rule", rule.Name, rule.Pos)
+	}
+}
+
+// checkRelationTypes reports relation types referencing unknown entities or relations
+func (c *checker) checkRelationTypes(relation *Relation) {
+	for _, relationType := range relation.Types {
+		target := c.schema.Entity(relationType.Type)
+		if target == nil {
+			c.report(relationType.Pos, SeverityError, "undefined-entity", "relation %s references undefined entity %s", relation.Name, relationType.Type)
+			continue
+		}
+		if relationType.Relation != "" && target.Relation(relationType.Relation) == nil && target.Permission(relationType.Relation) == nil {
+			c.report(relationType.Pos, SeverityError, "undefined-reference", "entity %s has no relation or permission %s", target.Name, relationType.Relation)
+		}
+	}
+}
+
+// checkRef reports references to undefined relations, permissions and attributes
+func (c *checker) checkRef(entity *Entity, ref *Ref) {
+	if ref.Member == "" {
+		attribute := entity.Attribute(ref.Name)
+		if attribute != nil && attribute.Type != "boolean" {
+			c.report(ref.Pos, SeverityError, "invalid-reference", "attribute %s of type %s can only be used through a rule", ref.Name, attribute.Type)
+		}
+		if attribute == nil && entity.Relation(ref.Name) == nil && entity.Permission(ref.Name) == nil {
+			c.report(ref.Pos, SeverityError, "undefined-reference", "entity %s has no relation, permission or attribute %s", entity.Name, ref.Name)
+		}
+		return
+	}
+
+	relation := entity.Relation(ref.Name)
+	if relation == nil {
+		if entity.Permission(ref.Name) != nil || entity.Attribute(ref.Name) != nil {
+			c.report(ref.Pos, SeverityError, "invalid-reference", "%s must be a relation to be followed by .%s", ref.Name, ref.Member)
+		} else {
+			c.report(ref.Pos, SeverityError, "undefined-reference", "entity %s has no relation %s", entity.Name, ref.Name)
+		}
+		return
+	}
+	targets := c.relationTargets(relation)
+	missing := 0
+	for _, target := range targets {
+		if target.Relation(ref.Member) == nil && target.Permission(ref.Member) == nil {
+			missing++
+		}
+	}
+	if missing > 0 && missing == len(targets) {
+		c.report(ref.Pos, SeverityError, "undefined-reference", "no entity of relation %s has a relation or permission %s", ref.Name, ref.Member)
+	}
+}
+
+// checkCall reports calls to undefined rules and arguments that do not match the rule parameters
+func (c *checker) checkCall(entity *Entity, call *CallExpr) {
+	rule := c.schema.Rule(call.Rule)
+	if rule == nil {
+		c.report(call.Pos, SeverityError, "undefined-rule", "rule %s is not defined", call.Rule)
+		return
+	}
+	if len(call.Args) != len(rule.Params) {
+		c.report(call.Pos, SeverityError, "argument-count", "rule %s takes %d arguments, %d given", rule.Name, len(rule.Params), len(call.Args))
+		return
+	}
+	for i, arg := range call.Args {
+		if arg.Name == contextName && arg.Member != "" {
+			continue
+		}
+		if arg.Member != "" {
+			c.report(arg.Pos, SeverityError, "invalid-reference", "rule arguments must be attributes or %s.<name>, found %s", contextName, arg)
+			continue
+		}
+		attribute := entity.Attribute(arg.Name)
+		if attribute == nil {
+			c.report(arg.Pos, SeverityError, "undefined-reference", "entity %s has no attribute %s", entity.Name, arg.Name)
+			continue
+		}
+		if attribute.Type != rule.Params[i].Type {
+			c.report(arg.Pos, SeverityError, "argument-type", "attribute %s is %s but parameter %s of rule %s is %s", attribute.Name, attribute.Type, rule.Params[i].Name, rule.Name, rule.Params[i].Type)
+		}
+	}
+}
+
+// checkCycles reports permissions that reference themselves through permissions of the same entity.
+// Cycles through other entities, e.g. parent.view, follow relationships and are allowed.
+func (c *checker) checkCycles(entity *Entity) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var visit func(permission *Permission, path []string)
+	visit = func(permission *Permission, path []string) {
+		state[permission.Name] = visiting
+		path = append(path, permission.Name)
+		Walk(permission.Expr, func(e Expr) {
+			ref, ok := e.(*Ref)
+			if !ok || ref.Member != "" {
+				return
+			}
+			next := entity.Permission(ref.Name)
+			if next == nil {
+				return
+			}
+			switch state[next.Name] {
+			case visiting:
+				start := 0
+				for path[start] != next.Name {
+					start++
+				}
+				cycle := append(append([]string{}, path[start:]...), next.Name)
+				c.report(ref.Pos, SeverityError, "cycle", "permission %s references itself through %s", next.Name, strings.Join(cycle, " -> "))
+			case 0:
+				visit(next, path)
+			}
+		})
+		state[permission.Name] = done
+	}
+	for _, permission := range entity.Permissions {
+		if state[permission.Name] == 0 {
+			visit(permission, nil)
+		}
+	}
+}
+
+// checkUnused reports relations, attributes and rules that no permission or relation type uses
+func (c *checker) checkUnused() {
+	used := map[string]bool{}
+	for _, entity := range c.schema.Entities {
+		for _, relation := range entity.Relations {
+			for _, relationType := range relation.Types {
+				used[relationType.Type+"#"+relationType.Relation] = true
+			}
+		}
+		for _, permission := range entity.Permissions {
+			Walk(permission.Expr, func(e Expr) {
+				switch e := e.(type) {
+				case *Ref:
+					used[entity.Name+"#"+e.Name] = true
+					if relation := entity.Relation(e.Name); relation != nil && e.Member != "" {
+						for _, target := range c.relationTargets(relation) {
+							used[target.Name+"#"+e.Member] = true
+						}
+					}
+				case *CallExpr:
+					used["rule "+e.Rule] = true
+					for _, arg := range e.Args {
+						used[entity.Name+"#"+arg.Name] = true
+					}
+				}
+			})
+		}
+	}
+
+	for _, entity := range c.schema.Entities {
+		for _, relation := range entity.Relations {
+			if !used[entity.Name+"#"+relation.Name] {
+				c.report(relation.Pos, SeverityWarning, "unused-relation", "relation %s of entity %s is not used by any permission", relation.Name, entity.Name)
+			}
+		}
+		for _, attribute := range entity.Attributes {
+			if !used[entity.Name+"#"+attribute.Name] {
+				c.report(attribute.Pos, SeverityWarning, "unused-attribute", "attribute %s of entity %s is not used by any permission", attribute.Name, entity.Name)
+			}
+		}
+	}
+	for _, rule := range c.schema.Rules {
+		if !used["rule "+rule.Name] {
+			c.report(rule.Pos, SeverityWarning, "unused-rule", "rule %s is not used by any permission", rule.Name)
+		}
+	}
+}
+
+// checkShadowed reports members named like an entity or a rule and rule parameters named like the request context
+func (c *checker) checkShadowed() {
+	for _, entity := range c.schema.Entities {
+		check := func(kind, name string, pos Position) {
+			if c.schema.Entity(name) != nil {
+				c.report(pos, SeverityWarning, "shadowed-name", "%s %s of entity %s has the name of entity %s", kind, name, entity.Name, name)
+			}
+			if c.schema.Rule(name) != nil {
+				c.report(pos, SeverityWarning, "shadowed-name", "%s %s of entity %s has the name of rule %s", kind, name, entity.Name, name)
+			}
+		}
+		for _, relation := range entity.Relations {
+			check("relation", relation.Name, relation.Pos)
+		}
+		for _, attribute := range entity.Attributes {
+			check("attribute", attribute.Name, attribute.Pos)
+			if attribute.Name == contextName {
+				c.report(attribute.Pos, SeverityWarning, "shadowed-name", "attribute %s of entity %s has the name used for the request context", attribute.Name, entity.Name)
+			}
+		}
+		for _, permission := range entity.Permissions {
+			check("permission", permission.Name, permission.Pos)
+		}
+	}
+	for _, rule := range c.schema.Rules {
+		for _, param := range rule.Params {
+			if param.Name == rule.Name {
+				c.report(param.Pos, SeverityWarning, "shadowed-name", "parameter %s has the name of its rule", param.Name)
+			}
+		}
+	}
+}
+
+// relationTargets returns the defined entities a relation can point to
+func (c *checker) relationTargets(relation *Relation) []*Entity {
+	targets := []*Entity{}
+	seen := map[string]bool{}
+	for _, relationType := range relation.Types {
+		target := c.schema.Entity(relationType.Type)
+		if target != nil && !seen[target.Name] {
+			seen[target.Name] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// HasErrors reports whether any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func sortDiagnostics(diagnostics []Diagnostic) []Diagnostic {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Pos.File != diagnostics[j].Pos.File {
+			return diagnostics[i].Pos.File < diagnostics[j].Pos.File
+		}
+		if diagnostics[i].Pos.Line != diagnostics[j].Pos.Line {
+			return diagnostics[i].Pos.Line < diagnostics[j].Pos.Line
+		}
+		return diagnostics[i].Pos.Column < diagnostics[j].Pos.Column
+	})
+	return diagnostics
+}
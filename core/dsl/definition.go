@@ -0,0 +1,224 @@
+package dsl
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+)
+
+// FromDefinition converts a schema read from permify into the ast of its perm source.
+// The definition does not keep the source order, entities and their members are sorted
+// by name and rule parameters are ordered by orderRuleParams. The returned warnings name
+// the rules whose parameter order could not be recovered, their source is not reliable.
+func FromDefinition(definition *v1.SchemaDefinition) (*Schema, []Diagnostic, error) {
+	schema := &Schema{}
+	for _, name := range sortedKeys(definition.GetEntityDefinitions()) {
+		entityDefinition := definition.GetEntityDefinitions()[name]
+		entity := &Entity{Name: name}
+		for _, relationName := range sortedKeys(entityDefinition.GetRelations()) {
+			relation := &Relation{Name: relationName}
+			for _, reference := range entityDefinition.GetRelations()[relationName].GetRelationReferences() {
+				relation.Types = append(relation.Types, &RelationType{Type: reference.GetType(), Relation: reference.GetRelation()})
+			}
+			entity.Relations = append(entity.Relations, relation)
+		}
+		for _, attributeName := range sortedKeys(entityDefinition.GetAttributes()) {
+			attributeType, err := attributeTypeName(entityDefinition.GetAttributes()[attributeName].GetType())
+			if err != nil {
+				return nil, nil, fmt.Errorf("attribute %s of entity %s: %w", attributeName, name, err)
+			}
+			entity.Attributes = append(entity.Attributes, &Attribute{Name: attributeName, Type: attributeType})
+		}
+		for _, permissionName := range sortedKeys(entityDefinition.GetPermissions()) {
+			e, err := childExpr(entityDefinition.GetPermissions()[permissionName].GetChild())
+			if err != nil {
+				return nil, nil, fmt.Errorf("permission %s of entity %s: %w", permissionName, name, err)
+			}
+			entity.Permissions = append(entity.Permissions, &Permission{Name: permissionName, Expr: e})
+		}
+		schema.Entities = append(schema.Entities, entity)
+	}
+
+	for _, name := range sortedKeys(definition.GetRuleDefinitions()) {
+		ruleDefinition := definition.GetRuleDefinitions()[name]
+		rule := &Rule{Name: name}
+		for _, paramName := range sortedKeys(ruleDefinition.GetArguments()) {
+			paramType, err := attributeTypeName(ruleDefinition.GetArguments()[paramName])
+			if err != nil {
+				return nil, nil, fmt.Errorf("parameter %s of rule %s: %w", paramName, name, err)
+			}
+			rule.Params = append(rule.Params, &Param{Name: paramName, Type: paramType})
+		}
+		body, err := unparseCEL(ruleDefinition.GetExpression())
+		if err != nil {
+			return nil, nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		rule.Body = body
+		schema.Rules = append(schema.Rules, rule)
+	}
+	return schema, orderRuleParams(schema), nil
+}
+
+// CodeRuleParamOrder is the code of the warning for rules whose parameter order is unknown
+const CodeRuleParamOrder = "rule-param-order"
+
+// orderRuleParams restores the parameter order of rules from their calls, since the definition
+// keeps parameters in a map and arguments bind by position. The order is taken from calls whose
+// arguments are all named like distinct parameters. Rules with more than one parameter and no such
+// call, or calls that disagree, keep the sorted order and are reported.
+func orderRuleParams(schema *Schema) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	for _, rule := range schema.Rules {
+		if len(rule.Params) < 2 {
+			continue
+		}
+		var ordered []*Param
+		conflict := false
+		for _, args := range callArgs(schema, rule.Name) {
+			order := paramOrder(rule, args)
+			if order == nil {
+				continue
+			}
+			if ordered != nil && !sameOrder(ordered, order) {
+				conflict = true
+				break
+			}
+			ordered = order
+		}
+		rule.UnknownParamOrder = conflict || ordered == nil
+		switch {
+		case conflict:
+			diagnostics = append(diagnostics, Diagnostic{Severity: SeverityWarning, Code: CodeRuleParamOrder,
+				Message: fmt.Sprintf("the calls of rule %s name its parameters in different orders, the parameter order is unknown", rule.Name)})
+		case ordered == nil:
+			diagnostics = append(diagnostics, Diagnostic{Severity: SeverityWarning, Code: CodeRuleParamOrder,
+				Message: fmt.Sprintf("no call of rule %s passes arguments named like its parameters, the parameter order is unknown", rule.Name)})
+		default:
+			rule.Params = ordered
+		}
+	}
+	return diagnostics
+}
+
+// paramOrder returns the parameters in the order of the arguments, nil unless every argument
+// is named like a different parameter
+func paramOrder(rule *Rule, args []*Ref) []*Param {
+	if len(args) != len(rule.Params) {
+		return nil
+	}
+	ordered := make([]*Param, 0, len(args))
+	placed := map[string]bool{}
+	for _, arg := range args {
+		name := arg.Name
+		if arg.Member != "" {
+			name = arg.Member
+		}
+		param := rule.Param(name)
+		if param == nil || placed[name] {
+			return nil
+		}
+		placed[name] = true
+		ordered = append(ordered, param)
+	}
+	return ordered
+}
+
+func sameOrder(a, b []*Param) bool {
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+// callArgs returns the arguments of every call of a rule
+func callArgs(schema *Schema, rule string) [][]*Ref {
+	calls := [][]*Ref{}
+	for _, entity := range schema.Entities {
+		for _, permission := range entity.Permissions {
+			Walk(permission.Expr, func(e Expr) {
+				if call, ok := e.(*CallExpr); ok && call.Rule == rule {
+					calls = append(calls, call.Args)
+				}
+			})
+		}
+	}
+	return calls
+}
+
+// childExpr converts a permission tree into an expression, the children of a rewrite are joined from left to right
+func childExpr(child *v1.Child) (Expr, error) {
+	if rewrite := child.GetRewrite(); rewrite != nil {
+		op := ""
+		switch rewrite.GetRewriteOperation() {
+		case v1.Rewrite_OPERATION_UNION:
+			op = OpOr
+		case v1.Rewrite_OPERATION_INTERSECTION:
+			op = OpAnd
+		case v1.Rewrite_OPERATION_EXCLUSION:
+			op = OpNot
+		default:
+			return nil, fmt.Errorf("unknown rewrite operation %s", rewrite.GetRewriteOperation())
+		}
+		var e Expr
+		for _, c := range rewrite.GetChildren() {
+			operand, err := childExpr(c)
+			if err != nil {
+				return nil, err
+			}
+			if e == nil {
+				e = operand
+				continue
+			}
+			e = &BinaryExpr{Op: op, Left: e, Right: operand}
+		}
+		if e == nil {
+			return nil, fmt.Errorf("%s without operands", rewrite.GetRewriteOperation())
+		}
+		return e, nil
+	}
+
+	leaf := child.GetLeaf()
+	switch {
+	case leaf.GetComputedUserSet() != nil:
+		return &Ref{Name: leaf.GetComputedUserSet().GetRelation()}, nil
+	case leaf.GetTupleToUserSet() != nil:
+		return &Ref{
+			Name:   leaf.GetTupleToUserSet().GetTupleSet().GetRelation(),
+			Member: leaf.GetTupleToUserSet().GetComputed().GetRelation(),
+		}, nil
+	case leaf.GetComputedAttribute() != nil:
+		return &Ref{Name: leaf.GetComputedAttribute().GetName()}, nil
+	case leaf.GetCall() != nil:
+		call := &CallExpr{Rule: leaf.GetCall().GetRuleName()}
+		for _, argument := range leaf.GetCall().GetArguments() {
+			if argument.GetContextAttribute() != nil {
+				call.Args = append(call.Args, &Ref{Name: contextName, Member: argument.GetContextAttribute().GetName()})
+				continue
+			}
+			call.Args = append(call.Args, &Ref{Name: argument.GetComputedAttribute().GetName()})
+		}
+		return call, nil
+	}
+	return nil, fmt.Errorf("unknown permission leaf %v", leaf)
+}
+
+// attributeTypeName returns the perm name of an attribute type, AttributeTypes follows the enum order
+func attributeTypeName(attributeType v1.AttributeType) (string, error) {
+	i := int(attributeType) - 1
+	if i < 0 || i >= len(AttributeTypes) {
+		return "", fmt.Errorf("unknown attribute type %s", attributeType)
+	}
+	return AttributeTypes[i], nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
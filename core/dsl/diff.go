@@ -0,0 +1,202 @@
+package dsl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ChangeKind tells whether a definition was added, removed or changed
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a difference of a single definition between two schemas
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Object is one of entity, relation, attribute, permission or rule
+	Object string `json:"object"`
+	Entity string `json:"entity,omitempty"`
+	Name   string `json:"name"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	// RemovedTypes are the relation types that a changed relation no longer accepts
+	RemovedTypes []*RelationType `json:"-"`
+	// Breaking is set by callers that found data depending on the removed definition
+	Breaking bool   `json:"breaking"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (c Change) String() string {
+	name := c.Name
+	if c.Entity != "" && c.Object != "entity" {
+		name = c.Entity + "#" + c.Name
+	}
+	separator := " "
+	if c.Object == "permission" {
+		separator = " = "
+	}
+	line := ""
+	switch c.Kind {
+	case Added:
+		line = fmt.Sprintf("+ %s %s", c.Object, name)
+		if c.To != "" {
+			line += separator + c.To
+		}
+	case Removed:
+		line = fmt.Sprintf("- %s %s", c.Object, name)
+		if c.From != "" {
+			line += separator + c.From
+		}
+	default:
+		line = fmt.Sprintf("~ %s %s: %s => %s", c.Object, name, c.From, c.To)
+	}
+	if c.Breaking {
+		line += " [breaking: " + c.Reason + "]"
+	}
+	return line
+}
+
+// Diff returns the changes needed to turn the from schema into the to schema.
+// Positions and source order are ignored, so a parsed file can be compared with FromDefinition.
+func Diff(from, to *Schema) []Change {
+	changes := []Change{}
+	for _, name := range unionNames(entityNames(from), entityNames(to)) {
+		fromEntity, toEntity := from.Entity(name), to.Entity(name)
+		switch {
+		case fromEntity == nil:
+			changes = append(changes, Change{Kind: Added, Object: "entity", Entity: name, Name: name})
+		case toEntity == nil:
+			changes = append(changes, Change{Kind: Removed, Object: "entity", Entity: name, Name: name})
+		}
+		relationChanges := diffMembers(name, "relation", relationDefinitions(fromEntity), relationDefinitions(toEntity))
+		for i, change := range relationChanges {
+			if change.Kind == Changed {
+				relationChanges[i].RemovedTypes = removedTypes(fromEntity.Relation(change.Name), toEntity.Relation(change.Name))
+			}
+		}
+		changes = append(changes, relationChanges...)
+		changes = append(changes, diffMembers(name, "attribute", attributeDefinitions(fromEntity), attributeDefinitions(toEntity))...)
+		changes = append(changes, diffMembers(name, "permission", permissionDefinitions(fromEntity), permissionDefinitions(toEntity))...)
+	}
+	unordered := map[string]bool{}
+	for _, rule := range append(append([]*Rule{}, from.Rules...), to.Rules...) {
+		unordered[rule.Name] = unordered[rule.Name] || rule.UnknownParamOrder
+	}
+	changes = append(changes, diffMembers("", "rule", ruleDefinitions(from, unordered), ruleDefinitions(to, unordered))...)
+	return changes
+}
+
+// diffMembers compares definitions by their normalized source
+func diffMembers(entity, object string, from, to map[string]string) []Change {
+	changes := []Change{}
+	for _, name := range unionNames(sortedKeys(from), sortedKeys(to)) {
+		fromSource, inFrom := from[name]
+		toSource, inTo := to[name]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Kind: Added, Object: object, Entity: entity, Name: name, To: toSource})
+		case !inTo:
+			changes = append(changes, Change{Kind: Removed, Object: object, Entity: entity, Name: name, From: fromSource})
+		case fromSource != toSource:
+			changes = append(changes, Change{Kind: Changed, Object: object, Entity: entity, Name: name, From: fromSource, To: toSource})
+		}
+	}
+	return changes
+}
+
+func entityNames(schema *Schema) []string {
+	names := []string{}
+	for _, entity := range schema.Entities {
+		names = append(names, entity.Name)
+	}
+	return names
+}
+
+func relationDefinitions(entity *Entity) map[string]string {
+	definitions := map[string]string{}
+	if entity == nil {
+		return definitions
+	}
+	for _, relation := range entity.Relations {
+		types := []string{}
+		for _, relationType := range relation.Types {
+			types = append(types, relationType.String())
+		}
+		sort.Strings(types)
+		definitions[relation.Name] = strings.Join(types, " ")
+	}
+	return definitions
+}
+
+func attributeDefinitions(entity *Entity) map[string]string {
+	definitions := map[string]string{}
+	if entity == nil {
+		return definitions
+	}
+	for _, attribute := range entity.Attributes {
+		definitions[attribute.Name] = attribute.Type
+	}
+	return definitions
+}
+
+func permissionDefinitions(entity *Entity) map[string]string {
+	definitions := map[string]string{}
+	if entity == nil {
+		return definitions
+	}
+	for _, permission := range entity.Permissions {
+		definitions[permission.Name] = permission.Expr.String()
+	}
+	return definitions
+}
+
+// ruleDefinitions compares rules by their parameters in declared order and body with collapsed whitespace.
+// The parameters of the unordered rules, those with an unknown parameter order in either schema, are sorted.
+func ruleDefinitions(schema *Schema, unordered map[string]bool) map[string]string {
+	definitions := map[string]string{}
+	for _, rule := range schema.Rules {
+		params := []string{}
+		for _, param := range rule.Params {
+			params = append(params, param.Name+" "+param.Type)
+		}
+		if unordered[rule.Name] {
+			sort.Strings(params)
+		}
+		definitions[rule.Name] = fmt.Sprintf("(%s) { %s }", strings.Join(params, ", "), strings.Join(strings.Fields(rule.Body), " "))
+	}
+	return definitions
+}
+
+// removedTypes returns the relation types of from that to does not accept anymore
+func removedTypes(from, to *Relation) []*RelationType {
+	kept := map[string]bool{}
+	for _, relationType := range to.Types {
+		kept[relationType.String()] = true
+	}
+	removed := []*RelationType{}
+	for _, relationType := range from.Types {
+		if !kept[relationType.String()] {
+			removed = append(removed, relationType)
+		}
+	}
+	return removed
+}
+
+// unionNames returns the sorted names found in either list
+func unionNames(a, b []string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
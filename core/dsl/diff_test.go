@@ -0,0 +1,94 @@
+package dsl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want []string
+	}{
+		{
+			name: "no changes when only order and whitespace differ",
+			from: "entity user {}\nentity doc {\n    relation owner @user\n    relation viewer @user\n}",
+			to:   "entity doc {\n  relation viewer   @user\n  relation owner @user\n}\nentity user {}",
+			want: []string{},
+		},
+		{
+			name: "added and removed definitions",
+			from: "entity user {}\nentity team {}\nentity doc {\n    relation owner @user\n}",
+			to:   "entity user {}\nentity doc {\n    relation owner @user\n    attribute public boolean\n    permission view = owner or public\n}",
+			want: []string{
+				"+ attribute doc#public boolean",
+				"+ permission doc#view = owner or public",
+				"- entity team",
+			},
+		},
+		{
+			name: "changed relation types",
+			from: "entity user {}\nentity team {}\nentity doc {\n    relation viewer @user @team @team#member\n}",
+			to:   "entity user {}\nentity team {}\nentity doc {\n    relation viewer @user @team#member\n}",
+			want: []string{"~ relation doc#viewer: @team @team#member @user => @team#member @user"},
+		},
+		{
+			name: "rule parameters in a different order",
+			from: "rule check(a integer, b string) {\n    a > 1\n}",
+			to:   "rule check(b string, a integer) {\n    a > 1\n}",
+			want: []string{"~ rule check: (a integer, b string) { a > 1 } => (b string, a integer) { a > 1 }"},
+		},
+		{
+			name: "rule body whitespace",
+			from: "rule check(a integer) {\n    a > 1\n}",
+			to:   "rule check(a integer) {\n  a  >  1\n}",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, change := range Diff(mustParse(t, tt.from), mustParse(t, tt.to)) {
+				got = append(got, change.String())
+			}
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRemovedTypes(t *testing.T) {
+	from := mustParse(t, "entity user {}\nentity team {}\nentity doc {\n    relation viewer @user @team @team#member\n}")
+	to := mustParse(t, "entity user {}\nentity team {}\nentity doc {\n    relation viewer @user @team#member\n}")
+	changes := Diff(from, to)
+	if len(changes) != 1 {
+		t.Fatalf("Diff() = %v, want a single change", changes)
+	}
+	removed := changes[0].RemovedTypes
+	if len(removed) != 1 || removed[0].Type != "team" || removed[0].Relation != "" {
+		t.Errorf("RemovedTypes = %v, want [@team]", removed)
+	}
+}
+
+func TestDiffUnknownRuleParamOrder(t *testing.T) {
+	from := mustParse(t, "rule check(b string, a integer) {\n    a > 1\n}")
+	to := mustParse(t, "rule check(a integer, b string) {\n    a > 1\n}")
+	to.Rules[0].UnknownParamOrder = true
+	if changes := Diff(from, to); len(changes) > 0 {
+		t.Errorf("Diff() = %v, want no changes for a rule with an unknown parameter order", changes)
+	}
+}
+
+func mustParse(t *testing.T, source string) *Schema {
+	t.Helper()
+	schema, err := Parse("test.perm", source)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return schema
+}
@@ -0,0 +1,325 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeKind is the kind of definition a graph node stands for
+type NodeKind string
+
+const (
+	NodeEntity     NodeKind = "entity"
+	NodeRelation   NodeKind = "relation"
+	NodeAttribute  NodeKind = "attribute"
+	NodePermission NodeKind = "permission"
+	NodeRule       NodeKind = "rule"
+)
+
+// Node is an entity, an entity member or a rule. Member ids are <entity>#<name>, rule ids rule:<name>
+type Node struct {
+	ID     string
+	Entity string
+	Name   string
+	Kind   NodeKind
+}
+
+// Edge points from a relation to its allowed subjects, or from a permission to what its expression uses
+type Edge struct {
+	From  string
+	To    string
+	Label string
+	// Type is set for edges from a relation to its relation types
+	Type bool
+}
+
+// Graph holds the definitions of a schema and the references between them
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// BuildGraph returns the graph of a schema. Relations point to their relation types and
+// permissions to the relations, permissions, attributes and rules they reference. Edges of
+// excluded operands are labeled not, edges through a relation are labeled with the relation.
+func BuildGraph(schema *Schema) *Graph {
+	g := &Graph{}
+	for _, entity := range schema.Entities {
+		g.Nodes = append(g.Nodes, &Node{ID: entity.Name, Entity: entity.Name, Name: entity.Name, Kind: NodeEntity})
+		for _, relation := range entity.Relations {
+			g.addMember(entity.Name, relation.Name, NodeRelation)
+			for _, relationType := range relation.Types {
+				to := relationType.Type
+				if relationType.Relation != "" {
+					to = relationType.Type + "#" + relationType.Relation
+				}
+				g.addEdge(&Edge{From: entity.Name + "#" + relation.Name, To: to, Type: true})
+			}
+		}
+		for _, attribute := range entity.Attributes {
+			g.addMember(entity.Name, attribute.Name, NodeAttribute)
+		}
+		for _, permission := range entity.Permissions {
+			g.addMember(entity.Name, permission.Name, NodePermission)
+		}
+	}
+	for _, rule := range schema.Rules {
+		g.Nodes = append(g.Nodes, &Node{ID: "rule:" + rule.Name, Name: rule.Name, Kind: NodeRule})
+	}
+
+	for _, entity := range schema.Entities {
+		for _, permission := range entity.Permissions {
+			g.addExprEdges(entity, entity.Name+"#"+permission.Name, permission.Expr, "")
+		}
+	}
+	g.Edges = g.knownEdges()
+	return g
+}
+
+func (g *Graph) addMember(entity, name string, kind NodeKind) {
+	g.Nodes = append(g.Nodes, &Node{ID: entity + "#" + name, Entity: entity, Name: name, Kind: kind})
+}
+
+// addEdge adds an edge unless the same edge already exists
+func (g *Graph) addEdge(edge *Edge) {
+	for _, e := range g.Edges {
+		if *e == *edge {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, edge)
+}
+
+func (g *Graph) addExprEdges(entity *Entity, from string, e Expr, label string) {
+	switch e := e.(type) {
+	case *BinaryExpr:
+		g.addExprEdges(entity, from, e.Left, label)
+		if e.Op == OpNot {
+			g.addExprEdges(entity, from, e.Right, OpNot)
+		} else {
+			g.addExprEdges(entity, from, e.Right, label)
+		}
+	case *Ref:
+		if e.Member == "" {
+			g.addEdge(&Edge{From: from, To: entity.Name + "#" + e.Name, Label: label})
+			return
+		}
+		relation := entity.Relation(e.Name)
+		if relation == nil {
+			return
+		}
+		edgeLabel := strings.TrimSpace(label + " " + e.Name)
+		for _, relationType := range relation.Types {
+			g.addEdge(&Edge{From: from, To: relationType.Type + "#" + e.Member, Label: edgeLabel})
+		}
+	case *CallExpr:
+		g.addEdge(&Edge{From: from, To: "rule:" + e.Rule, Label: label})
+		for _, arg := range e.Args {
+			if arg.Member == "" {
+				g.addEdge(&Edge{From: from, To: entity.Name + "#" + arg.Name, Label: label})
+			}
+		}
+	}
+}
+
+// knownEdges drops edges to undefined nodes, which validate reports as errors
+func (g *Graph) knownEdges() []*Edge {
+	edges := []*Edge{}
+	for _, edge := range g.Edges {
+		if g.node(edge.From) != nil && g.node(edge.To) != nil {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+func (g *Graph) node(id string) *Node {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// Focus returns the part of the graph around an entity, or around a member of the entity when
+// member is set. An entity keeps its own nodes and their direct references, a member keeps every
+// node it reaches through its edges.
+func (g *Graph) Focus(entity, member string) (*Graph, error) {
+	keep := map[string]bool{}
+	if member == "" {
+		if g.node(entity) == nil {
+			return nil, fmt.Errorf("entity %s is not defined", entity)
+		}
+		for _, node := range g.Nodes {
+			if node.Entity == entity {
+				keep[node.ID] = true
+			}
+		}
+		for _, edge := range g.Edges {
+			if g.node(edge.From).Entity == entity {
+				keep[edge.To] = true
+			}
+		}
+	} else {
+		start := entity + "#" + member
+		if g.node(start) == nil {
+			return nil, fmt.Errorf("entity %s has no relation, attribute or permission %s", entity, member)
+		}
+		keep[start] = true
+		queue := []string{start}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, edge := range g.Edges {
+				if edge.From == current && !keep[edge.To] {
+					keep[edge.To] = true
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+	}
+
+	focused := &Graph{}
+	for _, node := range g.Nodes {
+		if keep[node.ID] {
+			focused.Nodes = append(focused.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			focused.Edges = append(focused.Edges, edge)
+		}
+	}
+	return focused, nil
+}
+
+// groups returns the entities in order with their member nodes, and the rule nodes. An entity
+// gets a group when any of its nodes is in the graph, even if the entity node was focused away.
+func (g *Graph) groups() ([]string, map[string][]*Node, []*Node) {
+	entities := []string{}
+	members := map[string][]*Node{}
+	rules := []*Node{}
+	for _, node := range g.Nodes {
+		if node.Kind == NodeRule {
+			rules = append(rules, node)
+			continue
+		}
+		if _, ok := members[node.Entity]; !ok {
+			entities = append(entities, node.Entity)
+			members[node.Entity] = []*Node{}
+		}
+		if node.Kind != NodeEntity {
+			members[node.Entity] = append(members[node.Entity], node)
+		}
+	}
+	return entities, members, rules
+}
+
+var dotShapes = map[NodeKind]string{
+	NodeEntity:     "box",
+	NodeRelation:   "ellipse",
+	NodeAttribute:  "note",
+	NodePermission: "hexagon",
+	NodeRule:       "component",
+}
+
+// DOT renders the graph in the graphviz dot language with a cluster per entity
+func (g *Graph) DOT() string {
+	s := strings.Builder{}
+	s.WriteString("digraph schema {\n")
+	s.WriteString("    rankdir=LR;\n")
+	s.WriteString("    node [fontname=\"Helvetica\"];\n")
+	s.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	entities, members, rules := g.groups()
+	for _, entity := range entities {
+		fmt.Fprintf(&s, "\n    subgraph %q {\n", "cluster_"+entity)
+		fmt.Fprintf(&s, "        label=%q;\n", entity)
+		if node := g.node(entity); node != nil {
+			fmt.Fprintf(&s, "        %q [label=%q, shape=%s, style=bold];\n", node.ID, node.Name, dotShapes[node.Kind])
+		}
+		for _, node := range members[entity] {
+			fmt.Fprintf(&s, "        %q [label=%q, shape=%s];\n", node.ID, node.Name, dotShapes[node.Kind])
+		}
+		s.WriteString("    }\n")
+	}
+	if len(rules) > 0 {
+		s.WriteString("\n")
+	}
+	for _, node := range rules {
+		fmt.Fprintf(&s, "    %q [label=%q, shape=%s];\n", node.ID, node.Name, dotShapes[node.Kind])
+	}
+
+	if len(g.Edges) > 0 {
+		s.WriteString("\n")
+	}
+	for _, edge := range g.Edges {
+		attributes := []string{}
+		if edge.Label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", edge.Label))
+		}
+		if edge.Type {
+			attributes = append(attributes, "style=dashed")
+		}
+		if len(attributes) == 0 {
+			fmt.Fprintf(&s, "    %q -> %q;\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&s, "    %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attributes, ", "))
+		}
+	}
+	s.WriteString("}\n")
+	return s.String()
+}
+
+var mermaidShapes = map[NodeKind][2]string{
+	NodeEntity:     {"[", "]"},
+	NodeRelation:   {"(", ")"},
+	NodeAttribute:  {"[/", "/]"},
+	NodePermission: {"{{", "}}"},
+	NodeRule:       {"[[", "]]"},
+}
+
+// Mermaid renders the graph as a mermaid flowchart with a subgraph per entity. Nodes are
+// numbered n0, n1, ... and subgraphs e0, e1, ..., as names like entity_member and entity#member
+// would collide once turned into mermaid identifiers.
+func (g *Graph) Mermaid() string {
+	s := strings.Builder{}
+	s.WriteString("flowchart LR\n")
+
+	ids := map[string]string{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+	node := func(indent string, n *Node) {
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(&s, "%s%s%s\"%s\"%s\n", indent, ids[n.ID], shape[0], n.Name, shape[1])
+	}
+	entities, members, rules := g.groups()
+	for i, entity := range entities {
+		fmt.Fprintf(&s, "    subgraph e%d [\"%s\"]\n", i, entity)
+		if n := g.node(entity); n != nil {
+			node("        ", n)
+		}
+		for _, n := range members[entity] {
+			node("        ", n)
+		}
+		s.WriteString("    end\n")
+	}
+	for _, n := range rules {
+		node("    ", n)
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Type {
+			arrow = "-.->"
+		}
+		if edge.Label != "" {
+			fmt.Fprintf(&s, "    %s %s|%s| %s\n", ids[edge.From], arrow, edge.Label, ids[edge.To])
+		} else {
+			fmt.Fprintf(&s, "    %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,233 @@
+// Package dsl parses and checks permify schemas written in the perm dsl without a server connection
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a location in a schema file, lines and columns start at 1
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenAt
+	tokenHash
+	tokenDot
+	tokenComma
+	tokenAssign
+	tokenLParen
+	tokenRParen
+	tokenLBrace
+	tokenRBrace
+	tokenLBracket
+	tokenRBracket
+	tokenIllegal
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:      "end of file",
+	tokenIdent:    "identifier",
+	tokenAt:       "'@'",
+	tokenHash:     "'#'",
+	tokenDot:      "'.'",
+	tokenComma:    "','",
+	tokenAssign:   "'='",
+	tokenLParen:   "'('",
+	tokenRParen:   "')'",
+	tokenLBrace:   "'{'",
+	tokenRBrace:   "'}'",
+	tokenLBracket: "'['",
+	tokenRBracket: "']'",
+	tokenIllegal:  "illegal character",
+}
+
+var punctuation = map[byte]tokenKind{
+	'@': tokenAt,
+	'#': tokenHash,
+	'.': tokenDot,
+	',': tokenComma,
+	'=': tokenAssign,
+	'(': tokenLParen,
+	')': tokenRParen,
+	'{': tokenLBrace,
+	'}': tokenRBrace,
+	'[': tokenLBracket,
+	']': tokenRBracket,
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   Position
+}
+
+func (t token) String() string {
+	if t.kind == tokenIdent {
+		return fmt.Sprintf("'%s'", t.value)
+	}
+	if t.kind == tokenIllegal {
+		return fmt.Sprintf("illegal character '%s'", t.value)
+	}
+	return tokenNames[t.kind]
+}
+
+// lexer splits a schema into tokens, comments and whitespace are skipped
+type lexer struct {
+	file   string
+	source string
+	offset int
+	line   int
+	column int
+}
+
+func newLexer(file, source string) *lexer {
+	return &lexer{file: file, source: source, line: 1, column: 1}
+}
+
+func (l *lexer) position() Position {
+	return Position{File: l.file, Line: l.line, Column: l.column}
+}
+
+// advance moves past the next n bytes keeping track of lines and columns
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.offset < len(l.source); i++ {
+		if l.source[l.offset] == '\n' {
+			l.line++
+			l.column = 1
+		} else {
+			l.column++
+		}
+		l.offset++
+	}
+}
+
+// skip moves past whitespace and comments
+func (l *lexer) skip() error {
+	for l.offset < len(l.source) {
+		rest := l.source[l.offset:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			l.advance(1)
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			l.advance(end)
+		case strings.HasPrefix(rest, "/*"):
+			pos := l.position()
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return &Error{Pos: pos, Message: "unterminated block comment"}
+			}
+			l.advance(end + 4)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) next() (token, error) {
+	err := l.skip()
+	if err != nil {
+		return token{}, err
+	}
+	pos := l.position()
+	if l.offset >= len(l.source) {
+		return token{kind: tokenEOF, pos: pos}, nil
+	}
+
+	c := l.source[l.offset]
+	if kind, ok := punctuation[c]; ok {
+		l.advance(1)
+		return token{kind: kind, value: string(c), pos: pos}, nil
+	}
+	if isIdentStart(c) {
+		start := l.offset
+		for l.offset < len(l.source) && isIdentPart(l.source[l.offset]) {
+			l.advance(1)
+		}
+		return token{kind: tokenIdent, value: l.source[start:l.offset], pos: pos}, nil
+	}
+	l.advance(1)
+	return token{kind: tokenIllegal, value: string(c), pos: pos}, nil
+}
+
+// block returns the raw text up to the brace closing an already consumed '{'.
+// Braces inside the string literals of the cel body are not counted.
+func (l *lexer) block() (string, error) {
+	pos := l.position()
+	start := l.offset
+	depth := 1
+	for l.offset < len(l.source) {
+		switch l.source[l.offset] {
+		case '"', '\'':
+			if err := l.stringLiteral(); err != nil {
+				return "", err
+			}
+			continue
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				body := l.source[start:l.offset]
+				l.advance(1)
+				return body, nil
+			}
+		}
+		l.advance(1)
+	}
+	return "", &Error{Pos: pos, Message: "unterminated block, missing '}'"}
+}
+
+// stringLiteral moves past the cel string literal starting at the current quote. Triple quoted
+// strings may span lines, raw strings prefixed with r or R have no escapes.
+func (l *lexer) stringLiteral() error {
+	pos := l.position()
+	quote := l.source[l.offset : l.offset+1]
+	if strings.HasPrefix(l.source[l.offset:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	raw := l.offset > 0 && (l.source[l.offset-1] == 'r' || l.source[l.offset-1] == 'R') &&
+		(l.offset == 1 || !isIdentPart(l.source[l.offset-2]))
+	l.advance(len(quote))
+	for l.offset < len(l.source) {
+		switch {
+		case !raw && l.source[l.offset] == '\\':
+			l.advance(2)
+		case strings.HasPrefix(l.source[l.offset:], quote):
+			l.advance(len(quote))
+			return nil
+		case l.source[l.offset] == '\n' && len(quote) == 1:
+			return &Error{Pos: pos, Message: "unterminated string literal"}
+		default:
+			l.advance(1)
+		}
+	}
+	return &Error{Pos: pos, Message: "unterminated string literal"}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,66 @@
+package dsl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Patch applies a partial schema to a schema. Entities and rules of the patch that are not in the
+// schema are added. Relations, attributes and permissions of an existing entity replace the member
+// with the same name, whatever its kind, and are appended otherwise. Rules are replaced by name.
+// Deletes remove members given as <entity>#<member> after the patch is applied.
+func Patch(schema, patch *Schema, deletes []string) error {
+	for _, patchEntity := range patch.Entities {
+		entity := schema.Entity(patchEntity.Name)
+		if entity == nil {
+			schema.Entities = append(schema.Entities, patchEntity)
+			continue
+		}
+		for _, relation := range patchEntity.Relations {
+			entity.removeMember(relation.Name)
+			entity.Relations = append(entity.Relations, relation)
+		}
+		for _, attribute := range patchEntity.Attributes {
+			entity.removeMember(attribute.Name)
+			entity.Attributes = append(entity.Attributes, attribute)
+		}
+		for _, permission := range patchEntity.Permissions {
+			entity.removeMember(permission.Name)
+			entity.Permissions = append(entity.Permissions, permission)
+		}
+	}
+	for _, rule := range patch.Rules {
+		i := slices.IndexFunc(schema.Rules, func(r *Rule) bool { return r.Name == rule.Name })
+		if i < 0 {
+			schema.Rules = append(schema.Rules, rule)
+			continue
+		}
+		schema.Rules[i] = rule
+	}
+
+	for _, d := range deletes {
+		entityName, member, ok := strings.Cut(d, "#")
+		if !ok || entityName == "" || member == "" {
+			return fmt.Errorf("invalid delete %s, expected <entity>#<member>", d)
+		}
+		entity := schema.Entity(entityName)
+		if entity == nil {
+			return fmt.Errorf("entity %s is not defined", entityName)
+		}
+		if !entity.removeMember(member) {
+			return fmt.Errorf("entity %s has no relation, attribute or permission %s", entityName, member)
+		}
+	}
+	return nil
+}
+
+// removeMember removes the relation, attribute or permission with the name, it reports whether one was found
+func (e *Entity) removeMember(name string) bool {
+	relations := slices.DeleteFunc(e.Relations, func(r *Relation) bool { return r.Name == name })
+	attributes := slices.DeleteFunc(e.Attributes, func(a *Attribute) bool { return a.Name == name })
+	permissions := slices.DeleteFunc(e.Permissions, func(p *Permission) bool { return p.Name == name })
+	removed := len(relations) != len(e.Relations) || len(attributes) != len(e.Attributes) || len(permissions) != len(e.Permissions)
+	e.Relations, e.Attributes, e.Permissions = relations, attributes, permissions
+	return removed
+}
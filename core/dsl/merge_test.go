@@ -0,0 +1,66 @@
+package dsl
+
+import "testing"
+
+func TestPatch(t *testing.T) {
+	base := "entity user {}\n\nentity doc {\n    relation owner @user\n    relation viewer @user\n\n    attribute public boolean\n\n    permission view = owner or viewer or public\n}\n\nrule check(a integer) {\n    a > 1\n}\n"
+	tests := []struct {
+		name    string
+		patch   string
+		deletes []string
+		want    string
+	}{
+		{
+			name:  "new entity and rule are added",
+			patch: "entity team {\n    relation member @user\n}\n\nrule other(b string) {\n    b == \"x\"\n}",
+			want:  "entity user {}\n\nentity doc {\n    relation owner @user\n    relation viewer @user\n\n    attribute public boolean\n\n    permission view = owner or viewer or public\n}\n\nentity team {\n    relation member @user\n}\n\nrule check(a integer) {\n    a > 1\n}\n\nrule other(b string) {\n    b == \"x\"\n}\n",
+		},
+		{
+			name:  "members replace members of the same name whatever their kind",
+			patch: "entity doc {\n    relation editor @user\n    permission public = owner\n    permission view = owner or editor\n}",
+			want:  "entity user {}\n\nentity doc {\n    relation owner @user\n    relation viewer @user\n    relation editor @user\n\n    permission public = owner\n    permission view = owner or editor\n}\n\nrule check(a integer) {\n    a > 1\n}\n",
+		},
+		{
+			name:  "rules are replaced by name",
+			patch: "rule check(a integer) {\n    a > 2\n}",
+			want:  "entity user {}\n\nentity doc {\n    relation owner @user\n    relation viewer @user\n\n    attribute public boolean\n\n    permission view = owner or viewer or public\n}\n\nrule check(a integer) {\n    a > 2\n}\n",
+		},
+		{
+			name:    "deletes run after the patch",
+			patch:   "entity doc {\n    permission view = owner or public\n}",
+			deletes: []string{"doc#viewer"},
+			want:    "entity user {}\n\nentity doc {\n    relation owner @user\n\n    attribute public boolean\n\n    permission view = owner or public\n}\n\nrule check(a integer) {\n    a > 1\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := mustParse(t, base)
+			if err := Patch(schema, mustParse(t, tt.patch), tt.deletes); err != nil {
+				t.Fatalf("Patch() error = %v", err)
+			}
+			if got := Format(schema); got != tt.want {
+				t.Errorf("Patch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatchDeleteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete string
+	}{
+		{"missing member", "doc"},
+		{"empty entity", "#owner"},
+		{"unknown entity", "team#member"},
+		{"unknown member", "doc#editor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := mustParse(t, "entity user {}\nentity doc {\n    relation owner @user\n}")
+			if err := Patch(schema, &Schema{}, []string{tt.delete}); err == nil {
+				t.Errorf("Patch() deleting %s succeeded, want an error", tt.delete)
+			}
+		})
+	}
+}
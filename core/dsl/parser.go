@@ -0,0 +1,391 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a syntax error at a position of the schema
+type Error struct {
+	Pos     Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+// Parse parses the perm source, file is only used in positions
+func Parse(file, source string) (*Schema, error) {
+	p := &parser{lexer: newLexer(file, source)}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{File: file}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.isKeyword("entity"):
+			entity, err := p.parseEntity()
+			if err != nil {
+				return nil, err
+			}
+			schema.Entities = append(schema.Entities, entity)
+		case p.isKeyword("rule"):
+			rule, err := p.parseRule()
+			if err != nil {
+				return nil, err
+			}
+			schema.Rules = append(schema.Rules, rule)
+		default:
+			return nil, p.unexpected("'entity' or 'rule'")
+		}
+	}
+	return schema, nil
+}
+
+func (p *parser) next() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = t
+	return nil
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	return p.token.kind == tokenIdent && p.token.value == keyword
+}
+
+func (p *parser) unexpected(expected string) error {
+	return &Error{Pos: p.token.pos, Message: fmt.Sprintf("expected %s, found %s", expected, p.token)}
+}
+
+// expect consumes a token of the given kind and returns it
+func (p *parser) expect(kind tokenKind) (token, error) {
+	if p.token.kind != kind {
+		return token{}, p.unexpected(tokenNames[kind])
+	}
+	t := p.token
+	return t, p.next()
+}
+
+// parseEntity parses entity <name> { <relation|attribute|permission>* }
+func (p *parser) parseEntity() (*Entity, error) {
+	entity := &Entity{Pos: p.token.pos}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	entity.Name = name.value
+	_, err = p.expect(tokenLBrace)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.token.kind != tokenRBrace {
+		switch {
+		case p.isKeyword("relation"):
+			relation, err := p.parseRelation()
+			if err != nil {
+				return nil, err
+			}
+			entity.Relations = append(entity.Relations, relation)
+		case p.isKeyword("attribute"):
+			attribute, err := p.parseAttribute()
+			if err != nil {
+				return nil, err
+			}
+			entity.Attributes = append(entity.Attributes, attribute)
+		case p.isKeyword("permission"), p.isKeyword("action"):
+			permission, err := p.parsePermission()
+			if err != nil {
+				return nil, err
+			}
+			entity.Permissions = append(entity.Permissions, permission)
+		default:
+			return nil, p.unexpected("'relation', 'attribute', 'permission' or '}'")
+		}
+	}
+	return entity, p.next()
+}
+
+// parseRelation parses relation <name> @<type>[#<relation>] ...
+func (p *parser) parseRelation() (*Relation, error) {
+	relation := &Relation{Pos: p.token.pos}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	relation.Name = name.value
+
+	if p.token.kind != tokenAt {
+		return nil, p.unexpected("relation type starting with '@'")
+	}
+	for p.token.kind == tokenAt {
+		relationType := &RelationType{Pos: p.token.pos}
+		err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		typeName, err := p.expect(tokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		relationType.Type = typeName.value
+		if p.token.kind == tokenHash {
+			err = p.next()
+			if err != nil {
+				return nil, err
+			}
+			subjectRelation, err := p.expect(tokenIdent)
+			if err != nil {
+				return nil, err
+			}
+			relationType.Relation = subjectRelation.value
+		}
+		relation.Types = append(relation.Types, relationType)
+	}
+	return relation, nil
+}
+
+// parseAttribute parses attribute <name> <type>[[]]
+func (p *parser) parseAttribute() (*Attribute, error) {
+	attribute := &Attribute{Pos: p.token.pos}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	attribute.Name = name.value
+	attribute.Type, err = p.parseType()
+	return attribute, err
+}
+
+// parseType parses a type name with an optional [] array suffix
+func (p *parser) parseType() (string, error) {
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return "", err
+	}
+	if p.token.kind != tokenLBracket {
+		return name.value, nil
+	}
+	err = p.next()
+	if err != nil {
+		return "", err
+	}
+	_, err = p.expect(tokenRBracket)
+	return name.value + "[]", err
+}
+
+// parsePermission parses permission <name> = <expression>
+func (p *parser) parsePermission() (*Permission, error) {
+	permission := &Permission{Pos: p.token.pos}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	permission.Name = name.value
+	_, err = p.expect(tokenAssign)
+	if err != nil {
+		return nil, err
+	}
+	permission.Expr, err = p.parseOr()
+	return permission, err
+}
+
+// parseOr parses operands joined by or, which binds weaker than and and not
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(OpOr) {
+		pos := p.token.pos
+		err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Pos: pos, Op: OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses operands joined by and or not
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(OpAnd) || p.isKeyword(OpNot) {
+		pos, op := p.token.pos, p.token.value
+		err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Pos: pos, Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parsePrimary parses a parenthesized expression, a reference or a rule call
+func (p *parser) parsePrimary() (Expr, error) {
+	if p.token.kind == tokenLParen {
+		err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(tokenRParen)
+		return expr, err
+	}
+
+	if p.token.kind != tokenIdent || p.isKeyword(OpOr) || p.isKeyword(OpAnd) || p.isKeyword(OpNot) {
+		return nil, p.unexpected("relation, permission, attribute or rule call")
+	}
+	if p.peekCall() {
+		return p.parseCall()
+	}
+	return p.parseRef()
+}
+
+// peekCall reports whether the current identifier is followed by '('
+func (p *parser) peekCall() bool {
+	saved := *p.lexer
+	t, err := p.lexer.next()
+	*p.lexer = saved
+	return err == nil && t.kind == tokenLParen
+}
+
+// parseRef parses <name>[.<member>]
+func (p *parser) parseRef() (*Ref, error) {
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	ref := &Ref{Pos: name.pos, Name: name.value}
+	if p.token.kind != tokenDot {
+		return ref, nil
+	}
+	err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	member, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	ref.Member = member.value
+	return ref, nil
+}
+
+// parseCall parses <rule>(<ref>, ...)
+func (p *parser) parseCall() (*CallExpr, error) {
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	call := &CallExpr{Pos: name.pos, Rule: name.value}
+	_, err = p.expect(tokenLParen)
+	if err != nil {
+		return nil, err
+	}
+	for p.token.kind != tokenRParen {
+		if len(call.Args) > 0 {
+			_, err = p.expect(tokenComma)
+			if err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseRef()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+	}
+	return call, p.next()
+}
+
+// parseRule parses rule <name>(<param> <type>, ...) { <cel expression> }
+func (p *parser) parseRule() (*Rule, error) {
+	rule := &Rule{Pos: p.token.pos}
+	err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.expect(tokenIdent)
+	if err != nil {
+		return nil, err
+	}
+	rule.Name = name.value
+	_, err = p.expect(tokenLParen)
+	if err != nil {
+		return nil, err
+	}
+	for p.token.kind != tokenRParen {
+		if len(rule.Params) > 0 {
+			_, err = p.expect(tokenComma)
+			if err != nil {
+				return nil, err
+			}
+		}
+		paramName, err := p.expect(tokenIdent)
+		if err != nil {
+			return nil, err
+		}
+		param := &Param{Pos: paramName.pos, Name: paramName.value}
+		param.Type, err = p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		rule.Params = append(rule.Params, param)
+	}
+	err = p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// the body is a cel expression, it is kept as is instead of being tokenized
+	if p.token.kind != tokenLBrace {
+		return nil, p.unexpected(tokenNames[tokenLBrace])
+	}
+	body, err := p.lexer.block()
+	if err != nil {
+		return nil, err
+	}
+	rule.Body = strings.TrimSpace(body)
+	return rule, p.next()
+}
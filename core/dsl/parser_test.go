@@ -0,0 +1,134 @@
+package dsl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		check  func(t *testing.T, schema *Schema)
+	}{
+		{
+			name: "entity members",
+			source: `entity user {}
+
+entity document {
+    relation owner @user
+    relation viewer @user @team#member
+    attribute public boolean
+    attribute tags string[]
+    permission view = owner or viewer or public
+}`,
+			check: func(t *testing.T, schema *Schema) {
+				document := schema.Entity("document")
+				if document == nil {
+					t.Fatal("entity document not parsed")
+				}
+				if len(document.Relations) != 2 || len(document.Attributes) != 2 || len(document.Permissions) != 1 {
+					t.Fatalf("got %d relations, %d attributes, %d permissions", len(document.Relations), len(document.Attributes), len(document.Permissions))
+				}
+				viewer := document.Relation("viewer")
+				if len(viewer.Types) != 2 || viewer.Types[1].String() != "@team#member" {
+					t.Errorf("viewer types = %v", viewer.Types)
+				}
+				if got := document.Attributes[1].Type; got != "string[]" {
+					t.Errorf("tags type = %s", got)
+				}
+			},
+		},
+		{
+			name:   "operator precedence",
+			source: "entity doc {\n    permission view = a or b and c not d\n}",
+			check: func(t *testing.T, schema *Schema) {
+				or, ok := schema.Entity("doc").Permissions[0].Expr.(*BinaryExpr)
+				if !ok || or.Op != OpOr {
+					t.Fatalf("root expression = %v", schema.Entity("doc").Permissions[0].Expr)
+				}
+				not, ok := or.Right.(*BinaryExpr)
+				if !ok || not.Op != OpNot {
+					t.Fatalf("right operand = %v", or.Right)
+				}
+				if and, ok := not.Left.(*BinaryExpr); !ok || and.Op != OpAnd {
+					t.Errorf("not left operand = %v", not.Left)
+				}
+			},
+		},
+		{
+			name:   "tuple to userset and call",
+			source: "entity doc {\n    relation parent @doc\n    attribute level integer\n    permission view = parent.view or check(level, request.min)\n}\n\nrule check(level integer, min integer) {\n    level >= min\n}",
+			check: func(t *testing.T, schema *Schema) {
+				or := schema.Entity("doc").Permissions[0].Expr.(*BinaryExpr)
+				if ref, ok := or.Left.(*Ref); !ok || ref.Name != "parent" || ref.Member != "view" {
+					t.Errorf("left operand = %v", or.Left)
+				}
+				call, ok := or.Right.(*CallExpr)
+				if !ok || call.Rule != "check" || len(call.Args) != 2 || call.Args[1].Member != "min" {
+					t.Errorf("right operand = %v", or.Right)
+				}
+				rule := schema.Rule("check")
+				if rule == nil || len(rule.Params) != 2 || rule.Params[1].Name != "min" || rule.Body != "level >= min" {
+					t.Errorf("rule = %+v", rule)
+				}
+			},
+		},
+		{
+			name:   "braces in rule strings",
+			source: "rule named(name string) {\n    name == \"}\" || name == '{' || name == \"\\\"}\" || name == r\"\\\" || name == \"\"\"}\n}\"\"\"\n}",
+			check: func(t *testing.T, schema *Schema) {
+				rule := schema.Rule("named")
+				want := "name == \"}\" || name == '{' || name == \"\\\"}\" || name == r\"\\\" || name == \"\"\"}\n}\"\"\""
+				if rule == nil || rule.Body != want {
+					t.Errorf("body = %q, want %q", rule.Body, want)
+				}
+			},
+		},
+		{
+			name:   "comments",
+			source: "// users\nentity user {} // trailing\n/* block\ncomment */\nentity team {}",
+			check: func(t *testing.T, schema *Schema) {
+				if len(schema.Entities) != 2 {
+					t.Errorf("got %d entities", len(schema.Entities))
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse("test.perm", tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			tt.check(t, schema)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		line   int
+	}{
+		{"missing brace", "entity user {\n    relation owner @user\n", 3},
+		{"missing relation type", "entity doc {\n    relation owner\n}", 3},
+		{"missing permission expression", "entity doc {\n    permission view =\n}", 3},
+		{"unterminated rule", "rule check(a integer) {\n    a > 1\n", 1},
+		{"unterminated rule string", "rule check(a string) {\n    a == \"}\n}", 2},
+		{"unknown keyword", "entity doc {\n    relations owner @user\n}", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("test.perm", tt.source)
+			var syntaxErr *Error
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse() error = %v, want a syntax error", err)
+			}
+			if syntaxErr.Pos.Line != tt.line {
+				t.Errorf("error line = %d, want %d: %v", syntaxErr.Pos.Line, tt.line, err)
+			}
+		})
+	}
+}
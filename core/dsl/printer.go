@@ -0,0 +1,81 @@
+package dsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indent is the indentation of entity members and rule bodies
+const indent = "    "
+
+// Format returns the canonical perm source of a schema. Entity members are grouped
+// into relations, attributes and permissions, each group separated by a blank line.
+func Format(schema *Schema) string {
+	s := strings.Builder{}
+	for i, entity := range schema.Entities {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		formatEntity(&s, entity)
+	}
+	for i, rule := range schema.Rules {
+		if i > 0 || len(schema.Entities) > 0 {
+			s.WriteString("\n")
+		}
+		formatRule(&s, rule)
+	}
+	return s.String()
+}
+
+func formatEntity(s *strings.Builder, entity *Entity) {
+	if len(entity.Relations) == 0 && len(entity.Attributes) == 0 && len(entity.Permissions) == 0 {
+		fmt.Fprintf(s, "entity %s {}\n", entity.Name)
+		return
+	}
+
+	groups := [][]string{}
+	relations := []string{}
+	for _, relation := range entity.Relations {
+		types := []string{}
+		for _, relationType := range relation.Types {
+			types = append(types, relationType.String())
+		}
+		relations = append(relations, fmt.Sprintf("relation %s %s", relation.Name, strings.Join(types, " ")))
+	}
+	attributes := []string{}
+	for _, attribute := range entity.Attributes {
+		attributes = append(attributes, fmt.Sprintf("attribute %s %s", attribute.Name, attribute.Type))
+	}
+	permissions := []string{}
+	for _, permission := range entity.Permissions {
+		permissions = append(permissions, fmt.Sprintf("permission %s = %s", permission.Name, permission.Expr))
+	}
+	for _, group := range [][]string{relations, attributes, permissions} {
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	fmt.Fprintf(s, "entity %s {\n", entity.Name)
+	for i, group := range groups {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		for _, line := range group {
+			s.WriteString(indent + line + "\n")
+		}
+	}
+	s.WriteString("}\n")
+}
+
+func formatRule(s *strings.Builder, rule *Rule) {
+	params := []string{}
+	for _, param := range rule.Params {
+		params = append(params, param.Name+" "+param.Type)
+	}
+	fmt.Fprintf(s, "rule %s(%s) {\n", rule.Name, strings.Join(params, ", "))
+	for _, line := range strings.Split(rule.Body, "\n") {
+		s.WriteString(indent + strings.TrimSpace(line) + "\n")
+	}
+	s.WriteString("}\n")
+}
@@ -0,0 +1,55 @@
+package dsl
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "empty entity",
+			source: "entity user{ }",
+			want:   "entity user {}\n",
+		},
+		{
+			name:   "members grouped by kind",
+			source: "entity doc {\n  permission view = owner\n  attribute public boolean\n  relation owner @user\n}",
+			want:   "entity doc {\n    relation owner @user\n\n    attribute public boolean\n\n    permission view = owner\n}\n",
+		},
+		{
+			name:   "parentheses kept where needed",
+			source: "entity doc {\n    permission view = (a or b) and c\n    permission edit = a not (b not c)\n    permission read = a or (b or c)\n}",
+			want:   "entity doc {\n    permission view = (a or b) and c\n    permission edit = a not (b not c)\n    permission read = a or b or c\n}\n",
+		},
+		{
+			name:   "rules after entities",
+			source: "rule check(a integer, b string) {\n  a > 1 &&\n    b == \"x\"\n}\nentity user {}",
+			want:   "entity user {}\n\nrule check(a integer, b string) {\n    a > 1 &&\n    b == \"x\"\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse("test.perm", tt.source)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := Format(schema)
+			if got != tt.want {
+				t.Fatalf("Format() = %q, want %q", got, tt.want)
+			}
+			// formatting the formatted source again must not change it
+			reparsed, err := Parse("test.perm", got)
+			if err != nil {
+				t.Fatalf("Parse(Format()) error = %v", err)
+			}
+			if again := Format(reparsed); again != got {
+				t.Errorf("Format() is not stable, %q became %q", got, again)
+			}
+			if changes := Diff(schema, reparsed); len(changes) > 0 {
+				t.Errorf("round trip changed the schema: %v", changes)
+			}
+		})
+	}
+}
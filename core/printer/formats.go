@@ -0,0 +1,111 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alecthomas/chroma/quick"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+func printJSON(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(generic, "", "\t")
+	if err != nil {
+		return err
+	}
+	if Color {
+		err = quick.Highlight(w, string(data), "json", "terminal256", "monokai")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func printYAML(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	err = encoder.Encode(generic)
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// printNDJSON writes every element of the single list in v on its own line.
+// Values without exactly one list are written as a single line.
+func printNDJSON(w io.Writer, v interface{}) error {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	_, items, ok := listField(generic)
+	if !ok {
+		items = []interface{}{generic}
+	}
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printRaw(w io.Writer, v interface{}) error {
+	var data []byte
+	var err error
+	if message, ok := v.(proto.Message); ok {
+		data, err = protojson.Marshal(message)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// listField returns the name and elements of the only list field of a response,
+// ignoring pagination tokens. The value itself is returned when it already is a list.
+func listField(generic interface{}) (string, []interface{}, bool) {
+	switch value := generic.(type) {
+	case []interface{}:
+		return "", value, true
+	case map[string]interface{}:
+		var name string
+		var items []interface{}
+		found := false
+		for key, field := range value {
+			if key == "continuous_token" {
+				continue
+			}
+			list, ok := field.([]interface{})
+			if !ok || found {
+				return "", nil, false
+			}
+			name, items, found = key, list, true
+		}
+		return name, items, found
+	}
+	return "", nil, false
+}
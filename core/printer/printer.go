@@ -0,0 +1,126 @@
+// Package printer renders command responses in the output format selected with the --output flag
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Permify/permify-cli/core/logger"
+	"github.com/mattn/go-isatty"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Format is the name of an output format
+type Format string
+
+const (
+	// JSON prints indented json, highlighted when writing to a terminal
+	JSON Format = "json"
+	// YAML prints yaml documents
+	YAML Format = "yaml"
+	// NDJSON prints one compact json document per line
+	NDJSON Format = "ndjson"
+	// Raw prints the canonical protojson encoding of the response
+	Raw Format = "raw"
+	// Table prints a human readable table
+	Table Format = "table"
+	// CSV prints the rows of the table format as csv
+	CSV Format = "csv"
+)
+
+// Printer writes a value to w in a specific format
+type Printer interface {
+	Print(w io.Writer, v interface{}) error
+}
+
+// PrinterFunc adapts a function to the Printer interface
+type PrinterFunc func(w io.Writer, v interface{}) error
+
+// Print calls f(w, v)
+func (f PrinterFunc) Print(w io.Writer, v interface{}) error {
+	return f(w, v)
+}
+
+var printers = map[Format]Printer{
+	JSON:   PrinterFunc(printJSON),
+	YAML:   PrinterFunc(printYAML),
+	NDJSON: PrinterFunc(printNDJSON),
+	Raw:    PrinterFunc(printRaw),
+	Table:  PrinterFunc(printTable),
+	CSV:    PrinterFunc(printCSV),
+}
+
+var (
+	// Out is the writer all responses are printed to
+	Out io.Writer = os.Stdout
+	// Color enables syntax highlighting. It is disabled when stdout is not a terminal or NO_COLOR is set
+	Color = isatty.IsTerminal(os.Stdout.Fd()) && os.Getenv("NO_COLOR") == ""
+
+	current = JSON
+)
+
+// Register adds or replaces the printer for a format
+func Register(format Format, p Printer) {
+	printers[format] = p
+}
+
+// Formats returns the names of all registered formats
+func Formats() []string {
+	formats := []string{}
+	for format := range printers {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// SetFormat selects the output format used by Print
+func SetFormat(format string) error {
+	if _, ok := printers[Format(format)]; !ok {
+		return fmt.Errorf("unknown output format %q, must be one of %s", format, strings.Join(Formats(), ", "))
+	}
+	current = Format(format)
+	return nil
+}
+
+// Current returns the selected output format
+func Current() Format {
+	return current
+}
+
+// Streaming reports whether the selected format prints every response on lines of its own, so
+// that the pages of a list can be printed as they arrive instead of as a single document
+func Streaming() bool {
+	return current == NDJSON || current == Raw
+}
+
+// Print writes v to Out in the selected output format
+func Print(v interface{}) {
+	err := printers[current].Print(Out, v)
+	if err != nil {
+		logger.Log.Error("failed to print response", "format", current, "error", err)
+	}
+}
+
+// toGeneric converts v into maps, slices and scalars following its json encoding.
+// Protobuf messages are encoded with protojson using the proto field names.
+func toGeneric(v interface{}) (interface{}, error) {
+	var data []byte
+	var err error
+	if message, ok := v.(proto.Message); ok {
+		data, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	return generic, err
+}
@@ -0,0 +1,156 @@
+package printer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// TableRenderer is implemented by values that know how to lay themselves out as a table or csv.
+// Values that do not implement it are flattened into columns by their json field names.
+type TableRenderer interface {
+	Headers() []string
+	Rows() [][]string
+}
+
+func printTable(w io.Writer, v interface{}) error {
+	headers, rows, err := tableData(v)
+	if err != nil {
+		return err
+	}
+	renderTable(w, headers, rows)
+	return nil
+}
+
+// printCSV writes the rows of the table layout of v as csv with a header line
+func printCSV(w io.Writer, v interface{}) error {
+	headers, rows, err := tableData(v)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// tableData lays v out as a table, see TableRenderer
+func tableData(v interface{}) ([]string, [][]string, error) {
+	if renderer, ok := v.(TableRenderer); ok {
+		return renderer.Headers(), renderer.Rows(), nil
+	}
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name, items, ok := listField(generic)
+	if !ok {
+		flat := map[string]string{}
+		flatten("", generic, flat)
+		rows := [][]string{}
+		for _, key := range sortedKeys(flat) {
+			rows = append(rows, []string{key, flat[key]})
+		}
+		return []string{"key", "value"}, rows, nil
+	}
+
+	if name == "" {
+		name = "value"
+	}
+	columns := []string{}
+	seen := map[string]bool{}
+	flatItems := []map[string]string{}
+	for _, item := range items {
+		flat := map[string]string{}
+		flatten("", item, flat)
+		if _, isMap := item.(map[string]interface{}); !isMap {
+			flat = map[string]string{name: flat[""]}
+		}
+		for _, key := range sortedKeys(flat) {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+		flatItems = append(flatItems, flat)
+	}
+
+	rows := [][]string{}
+	for _, flat := range flatItems {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = flat[column]
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+func renderTable(w io.Writer, headers []string, rows [][]string) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(headers)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+// flatten writes the scalar leaves of value into out, joining nested keys with dots
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flatten(key, field, out)
+		}
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				data, _ := json.Marshal(item)
+				values = append(values, string(data))
+			default:
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		out[prefix] = strings.Join(values, ", ")
+	case float64:
+		out[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// visibleChoices is the number of choices shown at once by PagedChoice
+const visibleChoices = 10
+
+// PageLoader loads the page of choices following the continuous token.
+// An empty next token means there are no more pages.
+type PageLoader func(continuousToken string) (choices []string, nextToken string, err error)
+
+type pageLoadedMsg struct {
+	choices   []string
+	nextToken string
+	err       error
+}
+
+type pagedModel struct {
+	prompt    string
+	load      PageLoader
+	choices   []string
+	filter    string
+	cursor    int
+	offset    int
+	nextToken string
+	more      bool
+	loading   bool
+	err       error
+	choice    string
+	cancelled bool
+}
+
+func (m pagedModel) Init() tea.Cmd {
+	return m.loadPage("")
+}
+
+func (m pagedModel) loadPage(continuousToken string) tea.Cmd {
+	return func() tea.Msg {
+		choices, nextToken, err := m.load(continuousToken)
+		return pageLoadedMsg{choices: choices, nextToken: nextToken, err: err}
+	}
+}
+
+// filtered returns the choices containing the filter text
+func (m pagedModel) filtered() []string {
+	if m.filter == "" {
+		return m.choices
+	}
+	filtered := []string{}
+	for _, choice := range m.choices {
+		if strings.Contains(strings.ToLower(choice), strings.ToLower(m.filter)) {
+			filtered = append(filtered, choice)
+		}
+	}
+	return filtered
+}
+
+func (m pagedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pageLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		m.choices = append(m.choices, msg.choices...)
+		m.nextToken = msg.nextToken
+		m.more = msg.nextToken != "" && len(msg.choices) > 0
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			m.cancelled = true
+			return m, tea.Quit
+		case tea.KeyEnter:
+			filtered := m.filtered()
+			if len(filtered) == 0 {
+				return m, nil
+			}
+			m.choice = filtered[m.cursor]
+			return m, tea.Quit
+		case tea.KeyUp:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case tea.KeyDown:
+			if m.cursor < len(m.filtered())-1 {
+				m.cursor++
+			}
+		case tea.KeyBackspace:
+			if m.filter != "" {
+				_, size := utf8.DecodeLastRuneInString(m.filter)
+				m.filter = m.filter[:len(m.filter)-size]
+				m.cursor, m.offset = 0, 0
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.filter += string(msg.Runes)
+			m.cursor, m.offset = 0, 0
+		}
+	}
+
+	// keep the cursor inside the visible window
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visibleChoices {
+		m.offset = m.cursor - visibleChoices + 1
+	}
+
+	// load the next page once the user scrolls close to the end of the loaded choices
+	if m.more && !m.loading && m.cursor+visibleChoices >= len(m.filtered()) {
+		m.loading = true
+		return m, m.loadPage(m.nextToken)
+	}
+	return m, nil
+}
+
+func (m pagedModel) View() string {
+	s := strings.Builder{}
+	s.WriteString(Pink(fmt.Sprintf("%s: ", m.prompt)))
+	s.WriteString(m.filter)
+	s.WriteString("\n\n")
+
+	filtered := m.filtered()
+	end := min(m.offset+visibleChoices, len(filtered))
+	for i := m.offset; i < end; i++ {
+		if m.cursor == i {
+			s.WriteString(Blue("[•] "))
+		} else {
+			s.WriteString(Blue("[ ] "))
+		}
+		s.WriteString(filtered[i])
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	switch {
+	case m.loading:
+		s.WriteString(Blue("loading more..."))
+	case len(filtered) == 0:
+		s.WriteString(Warning("no matches"))
+	default:
+		s.WriteString(Blue("↑/↓ to move, type to filter, enter to select"))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+// PagedChoice shows a choice prompt that loads more pages of choices as the user
+// scrolls and filters the loaded choices by the typed text
+func PagedChoice(prompt string, load PageLoader) (string, error) {
+	p := tea.NewProgram(pagedModel{
+		prompt:  prompt,
+		load:    load,
+		loading: true,
+	})
+
+	m, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+	result, ok := m.(pagedModel)
+	if !ok {
+		return "", errors.New("unexpected prompt result")
+	}
+	if result.err != nil {
+		return "", result.err
+	}
+	if result.cancelled || result.choice == "" {
+		return "", errors.New("prompt cancelled")
+	}
+	return result.choice, nil
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/mattn/go-isatty"
+)
+
+// Progress renders a progress bar on stderr for long running operations.
+// Nothing is rendered when stderr is not a terminal.
+type Progress struct {
+	mu      sync.Mutex
+	bar     progress.Model
+	label   string
+	total   int
+	done    int
+	enabled bool
+}
+
+// NewProgress creates a progress bar for total units of work
+func NewProgress(label string, total int) *Progress {
+	p := &Progress{
+		bar:     progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
+		label:   label,
+		total:   total,
+		enabled: isatty.IsTerminal(os.Stderr.Fd()),
+	}
+	p.render()
+	return p
+}
+
+// Add marks n more units of work as done
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	p.render()
+}
+
+// Finish ends the progress bar line
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (p *Progress) render() {
+	if !p.enabled {
+		return
+	}
+	percent := 1.0
+	if p.total > 0 {
+		percent = float64(p.done) / float64(p.total)
+	}
+	fmt.Fprintf(os.Stderr, "\r%s %s %d/%d", Pink(p.label), p.bar.ViewAs(percent), p.done, p.total)
+}
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Permify/permify-cli/core/dsl"
+	v1 "github.com/Permify/permify-go/generated/base/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ParseAttribute parses an attribute written as <type>:<id>$<attribute>|<value type>:<value>
+func ParseAttribute(attributeStr string) (*v1.Attribute, error) {
+	entityAttribute, typedValue, found := strings.Cut(attributeStr, "|")
+	if !found {
+		return nil, errors.New("Attribute string should match pattern <type>:<id>$<attribute>|<value type>:<value>")
+	}
+	entityStr, attribute, found := strings.Cut(entityAttribute, "$")
+	if !found || attribute == "" {
+		return nil, errors.New("Attribute string should match pattern <type>:<id>$<attribute>|<value type>:<value>")
+	}
+	valueType, value, found := strings.Cut(typedValue, ":")
+	if !found {
+		return nil, errors.New("Attribute string should match pattern <type>:<id>$<attribute>|<value type>:<value>")
+	}
+	return NewAttribute(entityStr, attribute, valueType, value)
+}
+
+// NewAttribute builds an attribute from its string parts
+func NewAttribute(entityStr, attribute, valueType, value string) (*v1.Attribute, error) {
+	entity, err := ParseEntity(strings.TrimSpace(entityStr))
+	if err != nil {
+		return nil, err
+	}
+	attribute = strings.TrimSpace(attribute)
+	if attribute == "" {
+		return nil, errors.New("attribute must not be empty")
+	}
+	anyValue, err := NewAttributeValue(strings.TrimSpace(valueType), value)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Attribute{
+		Entity:    entity,
+		Attribute: attribute,
+		Value:     anyValue,
+	}, nil
+}
+
+// NewAttributeValue encodes value into the permify wrapper of the value type.
+// Array values are comma separated, see splitArray for items holding commas or spaces.
+func NewAttributeValue(valueType, value string) (*anypb.Any, error) {
+	if strings.HasSuffix(valueType, "[]") {
+		items, err := splitArray(value)
+		if err != nil {
+			return nil, err
+		}
+		return NewAttributeArrayValue(valueType, items)
+	}
+	var message proto.Message
+	switch valueType {
+	case "boolean":
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean value %q", value)
+		}
+		message = &v1.BooleanValue{Data: b}
+	case "string":
+		message = &v1.StringValue{Data: value}
+	case "integer":
+		i, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer value %q", value)
+		}
+		message = &v1.IntegerValue{Data: int32(i)}
+	case "double":
+		d, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double value %q", value)
+		}
+		message = &v1.DoubleValue{Data: d}
+	default:
+		return nil, fmt.Errorf("unknown attribute type %q, must be one of %s", valueType, strings.Join(dsl.AttributeTypes, ", "))
+	}
+	return anypb.New(message)
+}
+
+// NewAttributeArrayValue encodes items into the permify wrapper of the array value type
+func NewAttributeArrayValue(valueType string, items []string) (*anypb.Any, error) {
+	var message proto.Message
+	switch valueType {
+	case "boolean[]":
+		data := []bool{}
+		for _, item := range items {
+			b, err := strconv.ParseBool(strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("invalid boolean value %q", item)
+			}
+			data = append(data, b)
+		}
+		message = &v1.BooleanArrayValue{Data: data}
+	case "string[]":
+		message = &v1.StringArrayValue{Data: items}
+	case "integer[]":
+		data := []int32{}
+		for _, item := range items {
+			i, err := strconv.ParseInt(strings.TrimSpace(item), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer value %q", item)
+			}
+			data = append(data, int32(i))
+		}
+		message = &v1.IntegerArrayValue{Data: data}
+	case "double[]":
+		data := []float64{}
+		for _, item := range items {
+			d, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid double value %q", item)
+			}
+			data = append(data, d)
+		}
+		message = &v1.DoubleArrayValue{Data: data}
+	default:
+		return nil, fmt.Errorf("unknown attribute type %q, must be one of %s", valueType, strings.Join(dsl.AttributeTypes, ", "))
+	}
+	return anypb.New(message)
+}
+
+// AttributeValueToString decodes an attribute value into its type and comma separated value
+func AttributeValueToString(value *anypb.Any) (string, string, error) {
+	message, err := value.UnmarshalNew()
+	if err != nil {
+		return "", "", err
+	}
+	switch v := message.(type) {
+	case *v1.BooleanValue:
+		return "boolean", strconv.FormatBool(v.Data), nil
+	case *v1.BooleanArrayValue:
+		items := []string{}
+		for _, b := range v.Data {
+			items = append(items, strconv.FormatBool(b))
+		}
+		return "boolean[]", strings.Join(items, ","), nil
+	case *v1.StringValue:
+		return "string", v.Data, nil
+	case *v1.StringArrayValue:
+		items := []string{}
+		for _, item := range v.Data {
+			items = append(items, quoteArrayItem(item))
+		}
+		return "string[]", strings.Join(items, ","), nil
+	case *v1.IntegerValue:
+		return "integer", strconv.FormatInt(int64(v.Data), 10), nil
+	case *v1.IntegerArrayValue:
+		items := []string{}
+		for _, i := range v.Data {
+			items = append(items, strconv.FormatInt(int64(i), 10))
+		}
+		return "integer[]", strings.Join(items, ","), nil
+	case *v1.DoubleValue:
+		return "double", strconv.FormatFloat(v.Data, 'f', -1, 64), nil
+	case *v1.DoubleArrayValue:
+		items := []string{}
+		for _, d := range v.Data {
+			items = append(items, strconv.FormatFloat(d, 'f', -1, 64))
+		}
+		return "double[]", strings.Join(items, ","), nil
+	}
+	return "", "", fmt.Errorf("unknown attribute value type %s", value.GetTypeUrl())
+}
+
+// AttributeToString formats an attribute in the notation accepted by ParseAttribute
+func AttributeToString(attribute *v1.Attribute) string {
+	valueType, value, err := AttributeValueToString(attribute.GetValue())
+	if err != nil {
+		return fmt.Sprintf("%s$%s", EntityToString(attribute.GetEntity()), attribute.GetAttribute())
+	}
+	return fmt.Sprintf("%s$%s|%s:%s", EntityToString(attribute.GetEntity()), attribute.GetAttribute(), valueType, value)
+}
+
+// splitArray splits the comma separated items of an array value. Items are trimmed, so items
+// that are empty or hold commas, quotes or surrounding spaces are written as double quoted
+// strings, e.g. "a, b","". An empty value is an empty array.
+func splitArray(value string) ([]string, error) {
+	items := []string{}
+	if strings.TrimSpace(value) == "" {
+		return items, nil
+	}
+	rest := value
+	for {
+		rest = strings.TrimSpace(rest)
+		var item string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted item in array value %q", value)
+			}
+			item, _ = strconv.Unquote(quoted)
+			rest = strings.TrimSpace(rest[len(quoted):])
+			if rest != "" && rest[0] != ',' {
+				return nil, fmt.Errorf("unexpected %q after quoted item in array value %q", rest, value)
+			}
+		} else {
+			raw, _, _ := strings.Cut(rest, ",")
+			item = strings.TrimSpace(raw)
+			if item == "" {
+				return nil, fmt.Errorf("empty item in array value %q, quote empty strings as \"\"", value)
+			}
+			rest = rest[len(raw):]
+		}
+		items = append(items, item)
+		if rest == "" {
+			return items, nil
+		}
+		// skip the comma
+		rest = rest[1:]
+	}
+}
+
+// quoteArrayItem quotes a string array item that splitArray would not read back unchanged
+func quoteArrayItem(item string) string {
+	if item == "" || item != strings.TrimSpace(item) || strings.ContainsAny(item, `,"`) {
+		return strconv.Quote(item)
+	}
+	return item
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitArray(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"empty", "", []string{}},
+		{"trimmed items", " a , b,c ", []string{"a", "b", "c"}},
+		{"quoted items", `"a, b", " c ",""`, []string{"a, b", " c ", ""}},
+		{"escaped quote", `"say \"hi\"",x`, []string{`say "hi"`, "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitArray(tt.value)
+			if err != nil {
+				t.Fatalf("splitArray() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitArray() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArrayErrors(t *testing.T) {
+	for _, value := range []string{"a,,b", "a,", `"a`, `"a"b`} {
+		if items, err := splitArray(value); err == nil {
+			t.Errorf("splitArray(%q) = %q, want an error", value, items)
+		}
+	}
+}
+
+func TestStringArrayRoundTrip(t *testing.T) {
+	items := []string{"plain", "a,b", " spaced ", "", `quote"d`}
+	value, err := NewAttributeArrayValue("string[]", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, text, err := AttributeValueToString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := splitArray(text)
+	if err != nil {
+		t.Fatalf("splitArray(%q) error = %v", text, err)
+	}
+	if !slices.Equal(got, items) {
+		t.Errorf("round trip of %q through %q = %q", items, text, got)
+	}
+}
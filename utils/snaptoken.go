@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/permify-cli/core/config"
+	"github.com/Permify/permify-cli/core/logger"
+)
+
+// AddSnapTokenFlags registers the flags selecting the snapshot a permission or read request is evaluated on
+func AddSnapTokenFlags(cmd *cobra.Command) {
+	cmd.Flags().String("snap-token", "", "snap token of a data write, the request sees at least the data of that write")
+	cmd.Flags().Bool("latest", false, "use the snap token saved by the last write with --save-snap-token")
+	cmd.MarkFlagsMutuallyExclusive("snap-token", "latest")
+}
+
+// SnapToken returns the snap token selected with --snap-token or --latest, empty when none is set
+func SnapToken(cmd *cobra.Command) (string, error) {
+	if latest, _ := cmd.Flags().GetBool("latest"); latest {
+		if config.CliConfig.SnapToken == "" {
+			return "", errors.New("no snap token saved for the profile, write data with --save-snap-token first")
+		}
+		return config.CliConfig.SnapToken, nil
+	}
+	snapToken, _ := cmd.Flags().GetString("snap-token")
+	return snapToken, nil
+}
+
+// AddSaveSnapTokenFlag registers the flag persisting the snap token of a data write in the profile
+func AddSaveSnapTokenFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("save-snap-token", false, "save the snap token of the write in the profile, for permission and read commands with --latest")
+}
+
+// SaveSnapToken saves the snap token of a write in the profile when --save-snap-token is set
+func SaveSnapToken(cmd *cobra.Command, snapToken string) {
+	if save, _ := cmd.Flags().GetBool("save-snap-token"); !save || snapToken == "" {
+		return
+	}
+	if err := config.SaveSnapToken(snapToken); err != nil {
+		logger.Log.Warn("failed to save the snap token", "error", err)
+	}
+}
@@ -2,12 +2,13 @@
 package utils
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -15,20 +16,9 @@ import (
 	"github.com/Permify/permify-cli/templates"
 	"github.com/Permify/permify-cli/tui"
 	v1 "github.com/Permify/permify-go/generated/base/v1"
-	"github.com/alecthomas/chroma/quick"
 	"github.com/spf13/cobra"
 )
 
-// PrettyPrint readable json to terminal
-func PrettyPrint(i interface{}) string {
-	s, _ := json.MarshalIndent(i, "", "\t")
-	err := quick.Highlight(os.Stdout, string(s), "json", "terminal256", "monokai")
-	if err != nil {
-		logger.Log.Error("failed to highlight", "error", err)
-	}
-	return string(s)
-}
-
 // CheckIfUnknownSubcommand halts execution on passing unknown subcommand. Which is not usually an error
 func CheckIfUnknownSubcommand(cmd *cobra.Command, args []string) {
 	if len(args) == 0 {
@@ -134,7 +124,12 @@ func ParseSubject(subjectStr string) (*v1.Subject, error) {
 	}, nil
 }
 
+// ReadFileToString reads a perm schema file, - reads the schema from standard input
 func ReadFileToString(filePath string) (string, error) {
+	if filePath == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
 	if !strings.HasSuffix(filepath.Base(filePath), ".perm") {
         return "", fmt.Errorf("only perm schema files accepted")
     }
@@ -144,4 +139,90 @@ func ReadFileToString(filePath string) (string, error) {
     }
     fileContents := string(data)
     return fileContents, nil
-}
\ No newline at end of file
+}
+
+// SchemaFiles resolves perm files and glob patterns into a sorted list of files.
+// Glob matches that are not perm files are skipped, - stands for standard input and has to be used alone.
+func SchemaFiles(patterns []string) ([]string, error) {
+	if len(patterns) == 1 && patterns[0] == "-" {
+		return patterns, nil
+	}
+	seen := map[string]bool{}
+	files := []string{}
+	for _, pattern := range patterns {
+		if pattern == "-" {
+			return nil, errors.New("standard input can not be combined with other schema files")
+		}
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			globMatches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			if len(globMatches) == 0 {
+				return nil, fmt.Errorf("no schema files match %s", pattern)
+			}
+			matches = []string{}
+			for _, match := range globMatches {
+				if strings.HasSuffix(match, ".perm") {
+					matches = append(matches, match)
+				}
+			}
+		}
+		for _, match := range matches {
+			clean := filepath.Clean(match)
+			if !seen[clean] {
+				seen[clean] = true
+				files = append(files, clean)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no schema files given")
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ParseTuple parses a relationship written as <type>:<id>#<relation>@<type>:<id>#relation (subject relation is optional)
+func ParseTuple(tupleStr string) (*v1.Tuple, error) {
+	entityRelation, subjectStr, found := strings.Cut(tupleStr, "@")
+	if !found {
+		return nil, errors.New("Tuple string should match pattern <type>:<id>#<relation>@<type>:<id>#relation (subject relation is optional)")
+	}
+	entityStr, relation, found := strings.Cut(entityRelation, "#")
+	if !found || relation == "" {
+		return nil, errors.New("Tuple string should match pattern <type>:<id>#<relation>@<type>:<id>#relation (subject relation is optional)")
+	}
+	entity, err := ParseEntity(entityStr)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := ParseSubject(subjectStr)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Tuple{
+		Entity:   entity,
+		Relation: relation,
+		Subject:  subject,
+	}, nil
+}
+
+// EntityToString formats an entity as <type>:<id>
+func EntityToString(entity *v1.Entity) string {
+	return fmt.Sprintf("%s:%s", entity.GetType(), entity.GetId())
+}
+
+// SubjectToString formats a subject as <type>:<id>#relation, the relation is left out when empty
+func SubjectToString(subject *v1.Subject) string {
+	if subject.GetRelation() == "" {
+		return fmt.Sprintf("%s:%s", subject.GetType(), subject.GetId())
+	}
+	return fmt.Sprintf("%s:%s#%s", subject.GetType(), subject.GetId(), subject.GetRelation())
+}
+
+// TupleToString formats a tuple in the notation accepted by ParseTuple
+func TupleToString(tuple *v1.Tuple) string {
+	return fmt.Sprintf("%s#%s@%s", EntityToString(tuple.GetEntity()), tuple.GetRelation(), SubjectToString(tuple.GetSubject()))
+}